@@ -0,0 +1,115 @@
+// Package age decrypts snout config values encrypted in place with age X25519 keys.
+//
+// Encrypted values are written as ENC[base64 age ciphertext] anywhere snout reads config values from, and are
+// decrypted at load time once a Decryptor is registered with snout.WithDecryptor:
+//
+//	decryptor, err := age.DecryptorFromEnv("SNOUT_AGE_KEY")
+//	if err != nil {
+//		panic(err)
+//	}
+//
+//	kernel.Bootstrap(ctx, snout.WithDecryptor(decryptor))
+package age
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	agelib "filippo.io/age"
+)
+
+// ErrNoIdentity is an error indicating no age identity was found where one was expected.
+var ErrNoIdentity = errors.New("no age identity")
+
+// Decryptor decrypts ENC[...] config values with age X25519 identities. It implements snout.Decryptor.
+type Decryptor struct {
+	identities []agelib.Identity
+}
+
+// NewDecryptor returns a Decryptor for the identities in keys, one AGE-SECRET-KEY-1... per line as written by
+// age-keygen. Blank lines and # comments are ignored.
+func NewDecryptor(keys string) (*Decryptor, error) {
+	identities, err := agelib.ParseIdentities(strings.NewReader(keys))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Decryptor{identities: identities}, nil
+}
+
+// DecryptorFromEnv returns a Decryptor for the identities held by the env var name.
+func DecryptorFromEnv(name string) (*Decryptor, error) {
+	keys, ok := os.LookupEnv(name)
+	if !ok || strings.TrimSpace(keys) == "" {
+		return nil, fmt.Errorf("%w: env var %s is not set", ErrNoIdentity, name)
+	}
+
+	return NewDecryptor(keys)
+}
+
+// DecryptorFromFile returns a Decryptor for the identities held by the key file at path.
+func DecryptorFromFile(path string) (*Decryptor, error) {
+	keys, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrNoIdentity, err)
+	}
+
+	return NewDecryptor(string(keys))
+}
+
+// Decrypt implements snout.Decryptor, ciphertext being the base64 encoded age ciphertext found within ENC[...].
+func (d *Decryptor) Decrypt(ciphertext string) (string, error) {
+	raw, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", err
+	}
+
+	r, err := agelib.Decrypt(bytes.NewReader(raw), d.identities...)
+	if err != nil {
+		return "", err
+	}
+
+	plaintext, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+
+	return string(plaintext), nil
+}
+
+// Encrypt encrypts plaintext to the given age X25519 recipients, age1... public keys, returning the ENC[...] value to
+// write into config files.
+func Encrypt(plaintext string, recipients ...string) (string, error) {
+	parsed := make([]agelib.Recipient, 0, len(recipients))
+
+	for _, recipient := range recipients {
+		r, err := agelib.ParseX25519Recipient(recipient)
+		if err != nil {
+			return "", err
+		}
+
+		parsed = append(parsed, r)
+	}
+
+	var buf bytes.Buffer
+
+	w, err := agelib.Encrypt(&buf, parsed...)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := io.WriteString(w, plaintext); err != nil {
+		return "", err
+	}
+
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+
+	return "ENC[" + base64.StdEncoding.EncodeToString(buf.Bytes()) + "]", nil
+}
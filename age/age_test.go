@@ -0,0 +1,159 @@
+package age_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	agelib "filippo.io/age"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/chiguirez/snout/v3"
+	"github.com/chiguirez/snout/v3/age"
+)
+
+var _ snout.Decryptor = (*age.Decryptor)(nil)
+
+type ageSuite struct {
+	suite.Suite
+	identity *agelib.X25519Identity
+}
+
+func TestAge(t *testing.T) {
+	suite.Run(t, new(ageSuite))
+}
+
+func (s *ageSuite) SetupTest() {
+	identity, err := agelib.GenerateX25519Identity()
+	s.Require().NoError(err)
+
+	s.identity = identity
+}
+
+func (s *ageSuite) TestRoundTrip() {
+	s.Run("Given a value encrypted to an age recipient", func() {
+		value, err := age.Encrypt("hunter2", s.identity.Recipient().String())
+		s.Require().NoError(err)
+		s.Require().True(strings.HasPrefix(value, "ENC["))
+
+		s.Run("When it is decrypted with the matching identity", func() {
+			decryptor, err := age.NewDecryptor(s.identity.String())
+			s.Require().NoError(err)
+
+			plaintext, err := decryptor.Decrypt(strings.TrimSuffix(strings.TrimPrefix(value, "ENC["), "]"))
+
+			s.Run("Then the plaintext is returned", func() {
+				s.Require().NoError(err)
+				s.Require().Equal("hunter2", plaintext)
+			})
+		})
+	})
+}
+
+func (s *ageSuite) TestWrongIdentity() {
+	s.Run("Given a value encrypted to another recipient", func() {
+		other, err := agelib.GenerateX25519Identity()
+		s.Require().NoError(err)
+
+		value, err := age.Encrypt("hunter2", other.Recipient().String())
+		s.Require().NoError(err)
+
+		s.Run("When the configuration is Checked", func() {
+			decryptor, err := age.NewDecryptor(s.identity.String())
+			s.Require().NoError(err)
+
+			kernel := snout.Kernel[struct {
+				Password string `snout:"password"`
+			}]{}
+
+			err = kernel.Bootstrap(
+				context.TODO(),
+				snout.WithConfigReader(strings.NewReader("password: "+value), snout.FormatYAML),
+				snout.WithDecryptor(decryptor),
+			).Check()
+
+			s.Run("Then a decryption error naming the key is returned", func() {
+				s.Require().ErrorIs(err, snout.ErrDecryption)
+				s.Require().ErrorContains(err, "password:")
+			})
+		})
+	})
+}
+
+func (s *ageSuite) TestKernelDecryptsValues() {
+	s.Run("Given a config file with an encrypted value and the key in an env var", func() {
+		value, err := age.Encrypt("hunter2", s.identity.Recipient().String())
+		s.Require().NoError(err)
+
+		s.T().Setenv("SNOUT_AGE_KEY", "# created: offline\n"+s.identity.String()+"\n")
+
+		type config struct {
+			DB struct {
+				User     string `snout:"user"`
+				Password string `snout:"password"`
+			} `snout:"db"`
+		}
+
+		s.Run("When Kernel is Initialized", func() {
+			decryptor, err := age.DecryptorFromEnv("SNOUT_AGE_KEY")
+			s.Require().NoError(err)
+
+			cfgChan := make(chan config, 1)
+
+			kernel := snout.Kernel[config]{RunE: func(_ context.Context, cfg config) error {
+				cfgChan <- cfg
+
+				return nil
+			}}
+
+			err = kernel.Bootstrap(
+				context.TODO(),
+				snout.WithConfigReader(strings.NewReader("db:\n  user: app\n  password: "+value+"\n"), snout.FormatYAML),
+				snout.WithDecryptor(decryptor),
+			).Initialize()
+			s.Require().NoError(err)
+
+			s.Run("Then the value is decrypted", func() {
+				cfg := <-cfgChan
+				s.Require().Equal("app", cfg.DB.User)
+				s.Require().Equal("hunter2", cfg.DB.Password)
+			})
+		})
+	})
+}
+
+func (s *ageSuite) TestDecryptorFromFile() {
+	s.Run("Given an age key file", func() {
+		path := filepath.Join(s.T().TempDir(), "keys.txt")
+		s.Require().NoError(os.WriteFile(path, []byte(s.identity.String()+"\n"), 0o600))
+
+		s.Run("When a Decryptor is loaded from it", func() {
+			decryptor, err := age.DecryptorFromFile(path)
+
+			s.Run("Then values encrypted to its recipient are decrypted", func() {
+				s.Require().NoError(err)
+
+				value, err := age.Encrypt("hunter2", s.identity.Recipient().String())
+				s.Require().NoError(err)
+
+				plaintext, err := decryptor.Decrypt(strings.TrimSuffix(strings.TrimPrefix(value, "ENC["), "]"))
+				s.Require().NoError(err)
+				s.Require().Equal("hunter2", plaintext)
+			})
+		})
+	})
+}
+
+func (s *ageSuite) TestDecryptorFromMissingEnv() {
+	s.Run("Given no key env var", func() {
+		s.Run("When a Decryptor is loaded from it", func() {
+			_, err := age.DecryptorFromEnv("SNOUT_AGE_KEY_MISSING")
+
+			s.Run("Then a no identity error is returned", func() {
+				s.Require().ErrorIs(err, age.ErrNoIdentity)
+			})
+		})
+	})
+}
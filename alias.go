@@ -0,0 +1,91 @@
+package snout
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// ErrAliasConflict is an error indicating a key and one of its aliases are set to different values.
+var ErrAliasConflict = errors.New("alias conflict")
+
+// aliasFields returns the alias fields of every field, so that the former keys are bound to env vars as well.
+func aliasFields(fields []configField) []configField {
+	var aliases []configField
+
+	for _, f := range fields {
+		aliases = append(aliases, f.aliases()...)
+	}
+
+	return aliases
+}
+
+// registerAliasFlags defines a flag for every alias, taking its value as text since it's only read back through v.
+func registerAliasFlags(flagSet *pflag.FlagSet, fields []configField) {
+	for _, f := range fields {
+		for _, alias := range f.aliases() {
+			if alias.flagName() == "" || flagSet.Lookup(alias.flagName()) != nil {
+				continue
+			}
+
+			flagSet.String(alias.flagName(), "", "deprecated, use --"+f.flagName())
+
+			if f.typ().Kind() == reflect.Bool {
+				flagSet.Lookup(alias.flagName()).NoOptDefVal = "true"
+			}
+		}
+	}
+}
+
+// resolveAliases moves the values set through aliases to the keys they stand for and logs a deprecation warning for
+// every alias or deprecated key in use. The deprecated tag of a field with aliases describes its aliases, and the
+// field itself otherwise. It must run before defaults are set, so that only values given explicitly count as set.
+func resolveAliases(v *viper.Viper, fields []configField) error {
+	for _, f := range fields {
+		aliases := f.aliases()
+
+		if len(aliases) == 0 && f.deprecation() != "" && v.IsSet(f.key()) {
+			logger.Warn("Deprecated config key",
+				slog.String("key", f.key()),
+				slog.String("deprecated", f.deprecation()),
+			)
+		}
+
+		for _, alias := range aliases {
+			if !v.IsSet(alias.key()) {
+				continue
+			}
+
+			attrs := []any{slog.String("key", alias.key()), slog.String("replaced_by", f.key())}
+			if f.deprecation() != "" {
+				attrs = append(attrs, slog.String("deprecated", f.deprecation()))
+			}
+
+			logger.Warn("Deprecated config key", attrs...)
+
+			value := v.Get(alias.key())
+
+			if !v.IsSet(f.key()) {
+				v.Set(f.key(), value)
+
+				continue
+			}
+
+			if current := v.Get(f.key()); envString(current) != envString(value) {
+				if f.secret() {
+					return fmt.Errorf("%w: %s and its alias %s are set to different values", ErrAliasConflict, f.key(),
+						alias.key())
+				}
+
+				return fmt.Errorf("%w: %s is set to %q but its alias %s to %q",
+					ErrAliasConflict, f.key(), envString(current), alias.key(), envString(value))
+			}
+		}
+	}
+
+	return nil
+}
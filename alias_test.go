@@ -0,0 +1,112 @@
+package snout_test
+
+import (
+	"context"
+	"strings"
+
+	"github.com/chiguirez/snout/v3"
+)
+
+type aliasConfig struct {
+	Kafka struct {
+		Brokers string `snout:"brokers" alias:"broker_address,broker" deprecated:"use kafka.brokers"`
+		Topic   string `snout:"topic" default:"orders"`
+	} `snout:"kafka"`
+	Debug    bool   `snout:"debug" alias:"verbose"`
+	Password string `snout:"password" alias:"pass" secret:"true"`
+}
+
+func (s *snoutSuite) TestAlias() {
+	env := func(vars map[string]string) snout.Options {
+		return snout.WithLookupEnv(func(name string) (string, bool) {
+			value, ok := vars[name]
+
+			return value, ok
+		})
+	}
+
+	bootstrap := func(opts ...snout.Options) (aliasConfig, error) {
+		cfgChan := make(chan aliasConfig, 1)
+
+		kernel := snout.Kernel[aliasConfig]{RunE: func(_ context.Context, config aliasConfig) error {
+			cfgChan <- config
+
+			return nil
+		}}
+
+		opts = append([]snout.Options{snout.WithArgs(), env(nil)}, opts...)
+		if err := kernel.Bootstrap(context.TODO(), opts...).Initialize(); err != nil {
+			return aliasConfig{}, err
+		}
+
+		return <-cfgChan, nil
+	}
+
+	s.Run("Given a config Struct with aliased keys", func() {
+		s.Run("When the old key is set in a config file", func() {
+			cfg, err := bootstrap(snout.WithConfigReader(strings.NewReader("kafka:\n  broker_address: kafka:9092\n"),
+				snout.FormatYAML))
+
+			s.Run("Then its value is read into the new key", func() {
+				s.Require().NoError(err)
+				s.Require().Equal("kafka:9092", cfg.Kafka.Brokers)
+				s.Require().Equal("orders", cfg.Kafka.Topic)
+			})
+		})
+
+		s.Run("When the old key is set as an env var", func() {
+			cfg, err := bootstrap(snout.WithEnvVarPrefix("APP"), env(map[string]string{"APP_KAFKA_BROKER": "kafka:9093"}))
+
+			s.Run("Then its value is read into the new key", func() {
+				s.Require().NoError(err)
+				s.Require().Equal("kafka:9093", cfg.Kafka.Brokers)
+			})
+		})
+
+		s.Run("When the old key is given as a flag", func() {
+			cfg, err := bootstrap(snout.WithArgs("--kafka.broker_address=kafka:9094", "--verbose"))
+
+			s.Run("Then its value is read into the new key", func() {
+				s.Require().NoError(err)
+				s.Require().Equal("kafka:9094", cfg.Kafka.Brokers)
+				s.Require().True(cfg.Debug)
+			})
+		})
+
+		s.Run("When the old and new keys are set to the same value", func() {
+			cfg, err := bootstrap(
+				snout.WithConfigReader(strings.NewReader("kafka:\n  brokers: kafka:9092\n"), snout.FormatYAML),
+				env(map[string]string{"KAFKA_BROKER_ADDRESS": "kafka:9092"}),
+			)
+
+			s.Run("Then the value is read", func() {
+				s.Require().NoError(err)
+				s.Require().Equal("kafka:9092", cfg.Kafka.Brokers)
+			})
+		})
+
+		s.Run("When the old and new keys are set to different values", func() {
+			_, err := bootstrap(
+				snout.WithConfigReader(strings.NewReader("kafka:\n  brokers: kafka:9092\n"), snout.FormatYAML),
+				env(map[string]string{"KAFKA_BROKER_ADDRESS": "kafka:9093"}),
+			)
+
+			s.Run("Then an alias conflict error is returned", func() {
+				s.Require().ErrorIs(err, snout.ErrConfig)
+				s.Require().ErrorIs(err, snout.ErrAliasConflict)
+				s.Require().ErrorContains(err,
+					`kafka.brokers is set to "kafka:9092" but its alias kafka.broker_address to "kafka:9093"`)
+			})
+		})
+
+		s.Run("When a secret key and its alias are set to different values", func() {
+			_, err := bootstrap(snout.WithArgs("--password=hunter2", "--pass=hunter3"))
+
+			s.Run("Then the alias conflict error leaves the values out", func() {
+				s.Require().ErrorIs(err, snout.ErrAliasConflict)
+				s.Require().ErrorContains(err, "password and its alias pass are set to different values")
+				s.Require().NotContains(err.Error(), "hunter")
+			})
+		})
+	})
+}
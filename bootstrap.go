@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"maps"
 	"os"
 	"os/signal"
 	"reflect"
@@ -12,7 +14,6 @@ import (
 	"syscall"
 	"time"
 
-	"github.com/go-playground/validator/v10"
 	"github.com/mitchellh/mapstructure"
 	"github.com/octago/sflags"
 	"github.com/octago/sflags/gen/gpflag"
@@ -26,20 +27,56 @@ var logger = slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{AddSou
 type ServiceConfig any
 
 // Kernel represents a service kernel with a run function.
+//
+// OnReload, when set, is called with the new configuration while RunE is running every time the config file or a
+// source registered through Options reports a change and the reloaded configuration is valid. Features of the
+// configuration are updated on reload whether OnReload is set or not.
 type Kernel[T ServiceConfig] struct {
-	RunE func(ctx context.Context, cfg T) error
+	RunE     func(ctx context.Context, cfg T) error
+	OnReload func(ctx context.Context, cfg T) error
 }
 
 // Env represents the environment configuration.
 type Env struct {
-	VarFile    string
-	VarsPrefix string
+	VarFile      string
+	VarsPrefix   string
+	KeySeparator string
+	DotEnvFiles  []string
+	ConfigFile   string
+	ConfigFormat Format
+	ConfigReader io.Reader
+}
+
+// keySeparator returns the separator replacing the dots of nested keys in env var names, a single underscore by
+// default.
+func (e Env) keySeparator() string {
+	if e.KeySeparator == "" {
+		return "_"
+	}
+
+	return e.KeySeparator
 }
 
 // KernelOptions contains options for configuring the kernel.
 type KernelOptions struct {
 	ServiceName string
 	Env         Env
+	Args        []string
+	Output      io.Writer
+	Sources     []Source
+	Decryptor   Decryptor
+	LookupEnv   func(name string) (string, bool)
+	Environ     func() []string
+	Variants    map[reflect.Type]map[string]reflect.Type
+	Groups      []Group
+	TagName     string
+	KeyNaming   NamingStrategy
+	Signals     <-chan os.Signal
+	OnReady     func()
+
+	// configDocument is the document of Env.ConfigReader, read once by Bootstrap so that reloads read it again
+	// without touching the reader.
+	configDocument []byte
 }
 
 // Options is a function type for configuring KernelOptions.
@@ -50,9 +87,15 @@ func NewKernelOptions() *KernelOptions {
 	return &KernelOptions{
 		ServiceName: "",
 		Env: Env{
-			VarFile:    ".",
-			VarsPrefix: "",
+			VarFile:      ".",
+			VarsPrefix:   "",
+			KeySeparator: "_",
 		},
+		Args:      os.Args[1:],
+		Output:    os.Stdout,
+		LookupEnv: os.LookupEnv,
+		Environ:   os.Environ,
+		TagName:   "snout",
 	}
 }
 
@@ -70,6 +113,15 @@ func WithEnvVarPrefix(prefix string) Options {
 	}
 }
 
+// WithEnvKeySeparator sets the separator replacing the dots of nested keys in env var names in KernelOptions, a
+// single underscore by default. A double underscore keeps nesting apart from underscores within keys, binding
+// kafka.broker_address to APP_KAFKA__BROKER_ADDRESS.
+func WithEnvKeySeparator(separator string) Options {
+	return func(kernel *KernelOptions) {
+		kernel.Env.KeySeparator = separator
+	}
+}
+
 // WithEnvVarFolderLocation sets the folder location for environment variable files in KernelOptions.
 func WithEnvVarFolderLocation(folderLocation string) Options {
 	return func(kernel *KernelOptions) {
@@ -77,6 +129,54 @@ func WithEnvVarFolderLocation(folderLocation string) Options {
 	}
 }
 
+// WithDotEnvFiles sets the dotenv files loaded into the environment variable layer in KernelOptions. Files are read
+// in order, later ones overriding earlier ones, missing files are skipped and the process env wins over any of them.
+// Variables are matched with the env var prefix, as in APP_KAFKA_TOPIC=orders.
+func WithDotEnvFiles(paths ...string) Options {
+	return func(kernel *KernelOptions) {
+		kernel.Env.DotEnvFiles = paths
+	}
+}
+
+// WithArgs sets the command line arguments snout parses flags from in KernelOptions, os.Args[1:] by default. Flags
+// unknown to T are ignored.
+func WithArgs(args ...string) Options {
+	return func(kernel *KernelOptions) {
+		kernel.Args = args
+	}
+}
+
+// WithOutput sets the writer snout prints reports to in KernelOptions, os.Stdout by default.
+func WithOutput(w io.Writer) Options {
+	return func(kernel *KernelOptions) {
+		kernel.Output = w
+	}
+}
+
+// WithLookupEnv sets the function snout reads environment variables through in KernelOptions, os.LookupEnv by
+// default.
+func WithLookupEnv(lookup func(name string) (string, bool)) Options {
+	return func(kernel *KernelOptions) {
+		kernel.LookupEnv = lookup
+	}
+}
+
+// WithEnviron sets the function listing the environment variables, as name=value pairs, in KernelOptions, os.Environ
+// by default. It is only used to find the env vars keying the entries of maps, read through LookupEnv.
+func WithEnviron(environ func() []string) Options {
+	return func(kernel *KernelOptions) {
+		kernel.Environ = environ
+	}
+}
+
+// WithSignals sets the channel snout listens to for shutdown signals in KernelOptions, replacing SIGTERM and SIGINT.
+// The context handed to RunE is cancelled on the first value received.
+func WithSignals(signals <-chan os.Signal) Options {
+	return func(kernel *KernelOptions) {
+		kernel.Signals = signals
+	}
+}
+
 // Bootstrap initializes the kernel with given options, setting up context and fetching configuration.
 func (k *Kernel[T]) Bootstrap(ctx context.Context, opts ...Options) KernelBootstrap[T] {
 	kernelOpts := NewKernelOptions()
@@ -84,25 +184,72 @@ func (k *Kernel[T]) Bootstrap(ctx context.Context, opts ...Options) KernelBootst
 		opt(kernelOpts)
 	}
 
-	ctx = setUpSignalHandling(ctx)
-	cfg := k.fetchVars(kernelOpts)
+	ctx = setUpSignalHandling(ctx, kernelOpts.Signals)
 
-	return KernelBootstrap[T]{ctx, cfg, k.RunE}
+	var (
+		cfg       T
+		decrypted map[string]bool
+	)
+
+	err := readConfigDocument(kernelOpts)
+	if err == nil {
+		cfg, decrypted, err = k.fetchVars(ctx, kernelOpts)
+	}
+
+	return KernelBootstrap[T]{
+		context:  ctx,
+		cfg:      cfg,
+		runE:     k.RunE,
+		onReload: k.OnReload,
+		reload: func(ctx context.Context) (T, error) {
+			cfg, _, err := k.fetchVars(ctx, kernelOpts)
+
+			return cfg, err
+		},
+		options:   kernelOpts,
+		decrypted: decrypted,
+		err:       err,
+	}
 }
 
 // KernelBootstrap holds the context, configuration, and run function for the kernel.
 type KernelBootstrap[T ServiceConfig] struct {
-	context context.Context
-	cfg     T
-	runE    func(ctx context.Context, cfg T) error
+	context  context.Context
+	cfg      T
+	runE     func(ctx context.Context, cfg T) error
+	onReload func(ctx context.Context, cfg T) error
+	reload   func(ctx context.Context) (T, error)
+	options  *KernelOptions
+	// decrypted holds the keys of the fields holding values decrypted from ENC[...], masked in dumps as secrets are.
+	decrypted map[string]bool
+	err       error
 }
 
 // Initialize validates the configuration and runs the kernel.
+//
+// When started with --snout-generate[=dir] it writes the config templates for T into dir, the config folder by
+// default, and returns without running the kernel. When started with --check-config it reports the result of Check
+// instead of running the kernel, and when started with --print-config[=yaml|json|env] it prints the resolved
+// configuration.
 func (kb KernelBootstrap[T]) Initialize() (err error) {
-	validate := validator.New()
+	if dir, ok := lookupArg(kb.options.Args, "snout-generate"); ok {
+		if dir == "" {
+			dir = kb.options.Env.VarFile
+		}
+
+		return generateTemplates(dir, reflect.TypeOf(&kb.cfg).Elem(), kb.options)
+	}
+
+	if _, ok := lookupArg(kb.options.Args, "check-config"); ok {
+		return kb.reportCheck()
+	}
+
+	if format, ok := lookupArg(kb.options.Args, "print-config"); ok {
+		return kb.printConfig(Format(format))
+	}
 
-	if err = validate.Struct(kb.cfg); err != nil {
-		return fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	if err = kb.Check(); err != nil {
+		return err
 	}
 
 	defer func() {
@@ -118,7 +265,14 @@ func (kb KernelBootstrap[T]) Initialize() (err error) {
 		}
 	}()
 
-	return kb.runE(kb.context, kb.cfg)
+	ctx, cancel := context.WithCancel(withReady(kb.context, kb.options))
+	defer cancel()
+
+	if kb.onReload != nil || hasFeatures(fieldsOf(&kb.cfg, namingOf(kb.options))) {
+		go kb.watchSources(ctx)
+	}
+
+	return kb.runE(ctx, kb.cfg)
 }
 
 // ErrPanic is an error indicating a panic occurred.
@@ -127,83 +281,249 @@ var ErrPanic = errors.New("panic")
 // ErrValidation is an error indicating a validation failure.
 var ErrValidation = errors.New("validation error")
 
-// fetchVars fetches the configuration using Viper from environment variables and configuration files.
-func (k *Kernel[T]) fetchVars(options *KernelOptions) T {
+// ErrConfig is an error indicating the configuration could not be loaded or decoded.
+var ErrConfig = errors.New("config error")
+
+// fetchVars fetches the configuration using Viper from environment variables and configuration files, along with the
+// keys of the fields holding values decrypted from ENC[...].
+func (k *Kernel[T]) fetchVars(ctx context.Context, options *KernelOptions) (T, map[string]bool, error) {
 	var cfg T
 
-	viper.SetEnvPrefix(options.Env.VarsPrefix)
-	viper.AutomaticEnv()
-	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v := viper.New()
 
 	flagSet := pflag.NewFlagSet(options.ServiceName, pflag.ContinueOnError)
+	flagSet.ParseErrorsWhitelist.UnknownFlags = true
+	flagSet.SetOutput(io.Discard)
+
+	n := namingOf(options)
+	fields := fieldsOf(&cfg, n)
+	loader, generated := any(&cfg).(Loader)
 
-	if err := gpflag.ParseTo(&cfg, flagSet, sflags.FlagDivider("."), sflags.FlagTag("snout")); err != nil {
-		panic(err)
+	if err := checkFlagNames(fields); err != nil {
+		return cfg, nil, fmt.Errorf("%w: %w", ErrConfig, err)
 	}
 
-	if err := viper.BindPFlags(flagSet); err != nil {
-		panic(err)
+	if generated || !n.isDefault() || nestsEmbedded(reflect.TypeOf(cfg), n) {
+		defineFlags(flagSet, fields)
+	} else {
+		derived := pflag.NewFlagSet(options.ServiceName, pflag.ContinueOnError)
+		if err := gpflag.ParseTo(&cfg, derived, sflags.FlagDivider("."), sflags.FlagTag("snout")); err != nil {
+			return cfg, nil, fmt.Errorf("%w: %w", ErrConfig, err)
+		}
+
+		addFlags(flagSet, derived, fields)
 	}
 
-	viper.SetConfigName(options.ServiceName)
-	viper.AddConfigPath(options.Env.VarFile)
+	defineCollectionFlags(flagSet, fields, n)
+	registerAliasFlags(flagSet, fields)
 
-	if err := viper.ReadInConfig(); err == nil {
-		logger.Info("Using config file", slog.String("config file", viper.ConfigFileUsed()))
+	if err := flagSet.Parse(options.Args); err != nil && !errors.Is(err, pflag.ErrHelp) {
+		return cfg, nil, fmt.Errorf("%w: %w", ErrConfig, err)
 	}
 
-	setDefaultValues(reflect.TypeOf(&cfg).Elem(), "")
+	if err := bindFlags(v, flagSet, fields); err != nil {
+		return cfg, nil, fmt.Errorf("%w: %w", ErrConfig, err)
+	}
 
-	if err := viper.Unmarshal(&cfg, unmarshalWithStructTag("snout")); err != nil {
-		panic(err)
+	values, err := readConfig(options)
+	if err != nil {
+		return cfg, nil, fmt.Errorf("%w: %w", ErrConfig, err)
 	}
 
-	return cfg
-}
+	if err := mergeConfigMap(v, values, fields); err != nil {
+		return cfg, nil, fmt.Errorf("%w: %w", ErrConfig, err)
+	}
 
-// setUpSignalHandling sets up a context with signal notifications.
-func setUpSignalHandling(ctx context.Context) context.Context {
-	ctx, _ = signal.NotifyContext(ctx, syscall.SIGTERM, syscall.SIGINT)
+	sources, err := loadSources(ctx, options.Sources)
+	if err != nil {
+		return cfg, nil, fmt.Errorf("%w: %w", ErrConfig, err)
+	}
 
-	return ctx
+	for _, values := range sources {
+		if err := mergeConfigMap(v, values, fields); err != nil {
+			return cfg, nil, fmt.Errorf("%w: %w", ErrConfig, err)
+		}
+	}
+
+	dotEnv, err := loadDotEnvFiles(options.Env.DotEnvFiles, options.LookupEnv)
+	if err != nil {
+		return cfg, nil, fmt.Errorf("%w: %w", ErrConfig, err)
+	}
+
+	lookupEnv := func(name string) (string, bool) {
+		if value, ok := options.LookupEnv(name); ok && value != "" {
+			return value, true
+		}
+
+		value, ok := dotEnv[name]
+
+		return value, ok
+	}
+
+	envFields := append(append([]configField{}, fields...), aliasFields(fields)...)
+	if err := checkEnvNames(envFields, options.Env); err != nil {
+		return cfg, nil, fmt.Errorf("%w: %w", ErrConfig, err)
+	}
+
+	if err := mergeConfigMap(v, envConfigMap(envFields, options.Env, lookupEnv), fields); err != nil {
+		return cfg, nil, fmt.Errorf("%w: %w", ErrConfig, err)
+	}
+
+	collections, err := collectionEnvMap(fields, options.Env, n, lookupEnv, envNames(options.Environ, dotEnv))
+	if err != nil {
+		return cfg, nil, fmt.Errorf("%w: %w", ErrConfig, err)
+	}
+
+	if err := mergeConfigMap(v, collections, fields); err != nil {
+		return cfg, nil, fmt.Errorf("%w: %w", ErrConfig, err)
+	}
+
+	flagValues, err := collectionFlagMap(flagSet, fields, n)
+	if err != nil {
+		return cfg, nil, fmt.Errorf("%w: %w", ErrConfig, err)
+	}
+
+	if err := mergeConfigMap(v, flagValues, fields); err != nil {
+		return cfg, nil, fmt.Errorf("%w: %w", ErrConfig, err)
+	}
+
+	if err := resolveAliases(v, fields); err != nil {
+		return cfg, nil, fmt.Errorf("%w: %w", ErrConfig, err)
+	}
+
+	setDefaultValues(v, fields)
+	setElementDefaults(v, fields, n)
+
+	decrypted, err := decryptValues(v, fields, options.Decryptor)
+	if err != nil {
+		return cfg, nil, fmt.Errorf("%w: %w", ErrConfig, err)
+	}
+
+	if err := interpolate(v, fields, lookupEnv, decrypted); err != nil {
+		return cfg, nil, fmt.Errorf("%w: %w", ErrConfig, err)
+	}
+
+	if generated {
+		if err := loader.SnoutDecode(v.Get); err != nil {
+			return cfg, nil, fmt.Errorf("%w: %w", ErrConfig, err)
+		}
+	} else if err := decodeSettings(v.AllSettings(), &cfg, n, options.Variants); err != nil {
+		return cfg, nil, fmt.Errorf("%w: %w", ErrConfig, err)
+	}
+
+	bindFeatures(&cfg, fields)
+
+	return cfg, decrypted, nil
 }
 
-// setDefaultValues sets default values recursively for configuration fields.
-func setDefaultValues(t reflect.Type, path string) {
-	for i := 0; i < t.NumField(); i++ {
-		field := t.Field(i)
-		finalPath := constructFinalPath(path, field)
+// lookupArg reports whether the --name flag is present in args, along with the value given as --name=value.
+func lookupArg(args []string, name string) (string, bool) {
+	for _, arg := range args {
+		if arg == "--"+name {
+			return "", true
+		}
 
-		if field.Type.Kind() == reflect.Struct {
-			setDefaultValues(field.Type, finalPath)
-		} else {
-			setDefaultValue(finalPath, field)
+		if strings.HasPrefix(arg, "--"+name+"=") {
+			return strings.TrimPrefix(arg, "--"+name+"="), true
 		}
 	}
+
+	return "", false
 }
 
-// constructFinalPath constructs the final path for a field based on its tag and the current path.
-func constructFinalPath(path string, field reflect.StructField) string {
-	tag := field.Tag.Get("snout")
-	if path != "" {
-		return strings.ToUpper(fmt.Sprintf("%s.%s", path, tag))
+// setUpSignalHandling sets up a context cancelled on the first of signals, or on SIGTERM and SIGINT when nil.
+func setUpSignalHandling(ctx context.Context, signals <-chan os.Signal) context.Context {
+	if signals == nil {
+		ctx, _ = signal.NotifyContext(ctx, syscall.SIGTERM, syscall.SIGINT)
+
+		return ctx
 	}
 
-	return strings.ToUpper(tag)
+	ctx, cancel := context.WithCancel(ctx)
+
+	go func() {
+		defer cancel()
+
+		select {
+		case <-signals:
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx
+}
+
+// setDefaultValues sets the default values of the configuration fields.
+func setDefaultValues(v *viper.Viper, fields []configField) {
+	for _, f := range fields {
+		setDefaultValue(v, f.key(), f.field)
+	}
 }
 
 // setDefaultValue sets the default value for a field in Viper.
-func setDefaultValue(finalPath string, field reflect.StructField) {
+func setDefaultValue(v *viper.Viper, finalPath string, field reflect.StructField) {
 	if defaultValue := field.Tag.Get("default"); defaultValue != "" {
-		viper.SetDefault(finalPath, defaultValue)
+		v.SetDefault(finalPath, defaultValue)
 	}
 }
 
-// unmarshalWithStructTag sets the struct tag for unmarshaling configuration.
-func unmarshalWithStructTag(tag string) viper.DecoderConfigOption {
+// unmarshalWithNaming sets the struct tag and name matching for unmarshaling configuration.
+func unmarshalWithNaming(n naming) viper.DecoderConfigOption {
 	return func(config *mapstructure.DecoderConfig) {
-		config.TagName = tag
-		config.DecodeHook = mapstructure.ComposeDecodeHookFunc(customUnMarshallerHookFunc)
+		config.TagName = n.tag
+		config.MatchName = n.matchName
+		config.DecodeHook = mapstructure.ComposeDecodeHookFunc(customUnMarshallerHookFunc, featureHookFunc)
+	}
+}
+
+// decodeSettings decodes settings, the nested maps of the configuration, into cfg, a pointer to the configuration
+// struct, the way viper unmarshals them once embedded structs are flattened and the sections of interface fields
+// decoded into their variants.
+func decodeSettings(settings map[string]any, cfg any, n naming, variants map[reflect.Type]map[string]reflect.Type) error {
+	if err := resolveVariants(settings, reflect.TypeOf(cfg).Elem(), n, variants); err != nil {
+		return err
+	}
+
+	embedSettings(settings, reflect.TypeOf(cfg).Elem(), n)
+
+	config := &mapstructure.DecoderConfig{Result: cfg, WeaklyTypedInput: true}
+	unmarshalWithNaming(n)(config)
+
+	decoder, err := mapstructure.NewDecoder(config)
+	if err != nil {
+		return err
+	}
+
+	return decoder.Decode(settings)
+}
+
+// embedSettings copies settings, the nested maps decoded into struct t, under the Go name of every embedded struct
+// of t flattened into its keys, as mapstructure only squashes embedded structs regardless of their tags.
+func embedSettings(settings map[string]any, t reflect.Type, n naming) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" || !isNestedStruct(field.Type) {
+			continue
+		}
+
+		if n.flattened(field) {
+			embedded := maps.Clone(settings)
+			embedSettings(embedded, indirectType(field.Type), n)
+			settings[field.Name] = embedded
+
+			continue
+		}
+
+		name, ok := n.name(field)
+		if !ok {
+			continue
+		}
+
+		for key, value := range settings {
+			if nested, ok := value.(map[string]any); ok && n.matchName(key, name) {
+				embedSettings(nested, indirectType(field.Type), n)
+			}
+		}
 	}
 }
 
@@ -0,0 +1,174 @@
+package snout
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldError describes a configuration key that failed a validation rule.
+type FieldError struct {
+	Key   string
+	Rule  string
+	Param string
+}
+
+// Error implements error.
+func (e FieldError) Error() string {
+	if e.Param != "" {
+		return fmt.Sprintf("%s: failed on the '%s=%s' rule", e.Key, e.Rule, e.Param)
+	}
+
+	return fmt.Sprintf("%s: failed on the '%s' rule", e.Key, e.Rule)
+}
+
+// ValidationErrors holds every configuration key that failed validation. It is wrapped by ErrValidation, so it can
+// be retrieved with errors.As from the error returned by Initialize or Check.
+type ValidationErrors []FieldError
+
+// Error implements error.
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, fieldErr := range e {
+		msgs = append(msgs, fieldErr.Error())
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// Check loads, decodes and validates the configuration without running the kernel.
+func (kb KernelBootstrap[T]) Check() error {
+	if kb.err != nil {
+		return kb.err
+	}
+
+	return validateConfig(&kb.cfg, kb.options)
+}
+
+// reportCheck runs Check and prints either a success summary or every error found to the kernel output.
+func (kb KernelBootstrap[T]) reportCheck() error {
+	var buf bytes.Buffer
+
+	err := kb.Check()
+
+	var validationErrs ValidationErrors
+
+	switch {
+	case err == nil:
+		fields := fieldsOf(&kb.cfg, namingOf(kb.options))
+		fmt.Fprintf(&buf, "config OK: %d keys loaded and validated\n", len(fields))
+	case errors.As(err, &validationErrs):
+		fmt.Fprintf(&buf, "config invalid: %d keys failed validation\n", len(validationErrs))
+
+		for _, fieldErr := range validationErrs {
+			fmt.Fprintf(&buf, "  %s\n", fieldErr.Error())
+		}
+	default:
+		fmt.Fprintf(&buf, "config invalid: %s\n", err.Error())
+	}
+
+	if _, writeErr := kb.options.Output.Write(buf.Bytes()); writeErr != nil {
+		return writeErr
+	}
+
+	return err
+}
+
+// validateConfig validates cfg against its validate tags and the groups set in options, reporting failing fields by
+// their key.
+func validateConfig(cfg any, options *KernelOptions) error {
+	n := namingOf(options)
+
+	validationErrs, err := tagErrors(cfg, n)
+	if err != nil {
+		return err
+	}
+
+	groupErrs, err := groupErrors(cfg, fieldsOf(cfg, n), options.Groups)
+	if err != nil {
+		return err
+	}
+
+	if validationErrs = append(validationErrs, groupErrs...); len(validationErrs) > 0 {
+		return fmt.Errorf("%w: %w", ErrValidation, validationErrs)
+	}
+
+	return nil
+}
+
+// tagErrors validates cfg against its validate tags, reporting failing fields by their key as named by n. A
+// configuration with a generated loader validates itself.
+func tagErrors(cfg any, n naming) (ValidationErrors, error) {
+	if loader, ok := cfg.(Loader); ok {
+		return loader.SnoutValidate(), nil
+	}
+
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		if name, ok := n.name(field); ok {
+			return name
+		}
+
+		return "-"
+	})
+
+	err := validate.Struct(cfg)
+
+	keys := fieldKeys(reflect.TypeOf(cfg).Elem(), n)
+
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return nil, err
+	}
+
+	validationErrs := make(ValidationErrors, 0, len(fieldErrs))
+
+	for _, fieldErr := range fieldErrs {
+		key, ok := keys[fieldKey(fieldErr.StructNamespace())]
+		if !ok {
+			key = fieldKey(fieldErr.Namespace())
+		}
+
+		validationErrs = append(validationErrs, FieldError{
+			Key:   key,
+			Rule:  fieldErr.Tag(),
+			Param: fieldErr.Param(),
+		})
+	}
+
+	return validationErrs, nil
+}
+
+// fieldKeys maps the Go field path of every leaf field of t, such as Kafka.Topic, to its key as named by n. Fields
+// of flattened embedded structs are keyed without the name of the embedded type they are reached through.
+func fieldKeys(t reflect.Type, n naming) map[string]string {
+	keys := map[string]string{}
+
+	walkFields(t, "", n, func(f configField) {
+		names := make([]string, 0, len(f.index))
+
+		structType := t
+		for _, i := range f.index {
+			field := indirectType(structType).Field(i)
+			names = append(names, field.Name)
+			structType = field.Type
+		}
+
+		keys[strings.Join(names, ".")] = f.key()
+	})
+
+	return keys
+}
+
+// fieldKey turns a validator namespace such as Config.kafka.topic into the snout key kafka.topic.
+func fieldKey(namespace string) string {
+	if _, key, ok := strings.Cut(namespace, "."); ok {
+		return key
+	}
+
+	return namespace
+}
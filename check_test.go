@@ -0,0 +1,106 @@
+package snout_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+
+	"github.com/chiguirez/snout/v3"
+)
+
+type checkConfig struct {
+	Kafka struct {
+		BrokerAddress string `snout:"broker_address" validate:"required"`
+		Retries       int    `snout:"retries" default:"3" validate:"lte=5"`
+	} `snout:"kafka"`
+}
+
+func (s *snoutSuite) TestCheckValidationErrors() {
+	s.Run("Given a config Struct with a missing required key", func() {
+		kernel := snout.Kernel[checkConfig]{RunE: func(context.Context, checkConfig) error {
+			return errors.New("kernel must not run")
+		}}
+
+		s.Run("When the configuration is Checked", func() {
+			err := kernel.Bootstrap(context.TODO(), snout.WithArgs()).Check()
+
+			s.Run("Then the failing keys are reported by their snout path", func() {
+				s.Require().ErrorIs(err, snout.ErrValidation)
+
+				var validationErrs snout.ValidationErrors
+				s.Require().ErrorAs(err, &validationErrs)
+				s.Require().Equal(snout.ValidationErrors{
+					{Key: "kafka.broker_address", Rule: "required"},
+				}, validationErrs)
+			})
+		})
+	})
+}
+
+func (s *snoutSuite) TestCheckConfigFlag() {
+	s.Run("Given a kernel started with --check-config", func() {
+		ran := false
+
+		kernel := snout.Kernel[checkConfig]{RunE: func(context.Context, checkConfig) error {
+			ran = true
+
+			return nil
+		}}
+
+		s.Run("When Kernel is Initialized with an invalid configuration", func() {
+			var out bytes.Buffer
+
+			err := kernel.Bootstrap(
+				context.TODO(),
+				snout.WithArgs("--check-config"),
+				snout.WithOutput(&out),
+			).Initialize()
+
+			s.Run("Then the error list is printed and the kernel is not run", func() {
+				s.Require().ErrorIs(err, snout.ErrValidation)
+				s.Require().False(ran)
+				s.Require().Equal("config invalid: 1 keys failed validation\n"+
+					"  kafka.broker_address: failed on the 'required' rule\n", out.String())
+			})
+		})
+
+		s.Run("When Kernel is Initialized with a valid configuration", func() {
+			var out bytes.Buffer
+
+			s.T().Setenv("CHECK_KAFKA_BROKER_ADDRESS", "localhost:9092")
+
+			err := kernel.Bootstrap(
+				context.TODO(),
+				snout.WithEnvVarPrefix("CHECK"),
+				snout.WithArgs("--check-config"),
+				snout.WithOutput(&out),
+			).Initialize()
+
+			s.Run("Then a success summary is printed and the kernel is not run", func() {
+				s.Require().NoError(err)
+				s.Require().False(ran)
+				s.Require().Equal("config OK: 2 keys loaded and validated\n", out.String())
+			})
+		})
+	})
+}
+
+func (s *snoutSuite) TestCheckMalformedFile() {
+	s.Run("Given a malformed config file", func() {
+		kernel := snout.Kernel[checkConfig]{RunE: func(context.Context, checkConfig) error {
+			return nil
+		}}
+
+		s.Run("When the configuration is Checked", func() {
+			err := kernel.Bootstrap(
+				context.TODO(),
+				snout.WithServiceName("BROKEN"),
+				snout.WithEnvVarFolderLocation("./testdata/"),
+			).Check()
+
+			s.Run("Then a config error is returned", func() {
+				s.Require().ErrorIs(err, snout.ErrConfig)
+			})
+		})
+	})
+}
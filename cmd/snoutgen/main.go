@@ -0,0 +1,45 @@
+// Command snoutgen generates a snout loader for a configuration type, so that the kernel loads it without
+// reflection. It is meant to run through go generate, writing the loader of Config to config_snout.go:
+//
+//	//go:generate go run github.com/chiguirez/snout/v3/cmd/snoutgen -type Config
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/chiguirez/snout/v3/snoutgen"
+)
+
+func main() {
+	typeName := flag.String("type", "", "name of the configuration struct type")
+	output := flag.String("output", "", "output file name, <type>_snout.go by default")
+	flag.Parse()
+
+	if *typeName == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	dir := "."
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+
+	src, err := snoutgen.Generate(dir, *typeName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if *output == "" {
+		*output = filepath.Join(dir, snoutgen.FileName(*typeName))
+	}
+
+	if err := os.WriteFile(*output, src, 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
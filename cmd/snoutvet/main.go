@@ -0,0 +1,18 @@
+// Command snoutvet checks the snout struct tags of the types used as snout.Kernel[T]. It runs standalone or with go
+// vet:
+//
+//	snoutvet ./...
+//	go vet -vettool=$(which snoutvet) ./...
+//
+// The -tag, -key-naming and -env-separator flags match services bootstrapped with other naming options.
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/singlechecker"
+
+	"github.com/chiguirez/snout/v3/snoutvet"
+)
+
+func main() {
+	singlechecker.Main(snoutvet.Analyzer)
+}
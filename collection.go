@@ -0,0 +1,342 @@
+package snout
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// elementFields returns the leaf fields of the elements of a collection field, keyed relative to the element, or nil
+// when its elements are not structs.
+func elementFields(f configField, n naming) []configField {
+	elem := indirectType(f.typ().Elem())
+	if !isNestedStruct(elem) {
+		return nil
+	}
+
+	return configFields(elem, n)
+}
+
+// elementEnvSuffix returns the part of the env var name of an element field following the element index or map key,
+// e.g. TLS_CERT_FILE for tls.cert_file.
+func elementEnvSuffix(f configField, separator string) string {
+	return strings.ToUpper(strings.ReplaceAll(f.key(), ".", separator))
+}
+
+// collectionEnvMap maps the env vars setting the collections among fields, as resolved by lookup, to a nested map
+// keyed by snout keys. The env var of a collection holds it as JSON, as in APP_UPSTREAMS='[{"host":"a"}]'. Otherwise
+// lists of structs are read element by element from env vars indexed from 0, as in APP_UPSTREAMS_0_HOST, and maps
+// entry by entry from the env vars among names keyed by the lower cased map key, as in APP_TENANTS_ACME_DSN.
+func collectionEnvMap(fields []configField, env Env, n naming, lookup func(string) (string, bool),
+	names []string,
+) (map[string]any, error) {
+	cfg := map[string]any{}
+	separator := env.keySeparator()
+
+	for _, f := range fields {
+		name := f.envName(env)
+		if name == "" || !f.collection() {
+			continue
+		}
+
+		if value, ok := lookup(name); ok {
+			var doc any
+			if err := json.Unmarshal([]byte(value), &doc); err != nil {
+				return nil, fmt.Errorf("env var %s of %s is not valid JSON: %w", name, f.key(), err)
+			}
+
+			setNested(cfg, f.key(), doc)
+
+			continue
+		}
+
+		switch f.typ().Kind() {
+		case reflect.Slice:
+			if list := envList(name+separator, separator, elementFields(f, n), lookup); len(list) > 0 {
+				setNested(cfg, f.key(), list)
+			}
+		case reflect.Map:
+			entries := envEntries(name+separator, separator, elementFields(f, n), lookup, names)
+			if len(entries) > 0 {
+				setNested(cfg, f.key(), entries)
+			}
+		}
+	}
+
+	return cfg, nil
+}
+
+// envNames returns the names of the env vars listed by environ, when set, and of those read from dotenv files.
+func envNames(environ func() []string, dotEnv map[string]string) []string {
+	var names []string
+
+	if environ != nil {
+		for _, pair := range environ() {
+			name, _, _ := strings.Cut(pair, "=")
+			names = append(names, name)
+		}
+	}
+
+	for name := range dotEnv {
+		names = append(names, name)
+	}
+
+	return names
+}
+
+// envList reads the elements of a list of structs from env vars named prefix, the element index and the suffix of an
+// element field, stopping at the first index without any of them set.
+func envList(prefix, separator string, elems []configField, lookup func(string) (string, bool)) []any {
+	var list []any
+
+	for i := 0; ; i++ {
+		elem := map[string]any{}
+
+		for _, f := range elems {
+			if value, ok := lookup(prefix + strconv.Itoa(i) + separator + elementEnvSuffix(f, separator)); ok {
+				setNested(elem, f.key(), value)
+			}
+		}
+
+		if len(elem) == 0 {
+			return list
+		}
+
+		list = append(list, elem)
+	}
+}
+
+// envEntries reads the entries of a map from the env vars among names starting with prefix. The rest of the name is
+// the map key, followed by the suffix of an element field when the map holds structs.
+func envEntries(prefix, separator string, elems []configField, lookup func(string) (string, bool),
+	names []string,
+) map[string]any {
+	// Longer suffixes are tried first, so that POOL_SIZE is not taken for the SIZE of the entry ACME_POOL.
+	elems = slices.Clone(elems)
+	slices.SortStableFunc(elems, func(a, b configField) int {
+		return len(elementEnvSuffix(b, separator)) - len(elementEnvSuffix(a, separator))
+	})
+
+	entries := map[string]any{}
+
+	for _, name := range names {
+		rest, ok := strings.CutPrefix(name, prefix)
+		if !ok || rest == "" {
+			continue
+		}
+
+		value, ok := lookup(name)
+		if !ok {
+			continue
+		}
+
+		if elems == nil {
+			entries[strings.ToLower(rest)] = value
+
+			continue
+		}
+
+		for _, f := range elems {
+			key, ok := strings.CutSuffix(rest, separator+elementEnvSuffix(f, separator))
+			if !ok || key == "" {
+				continue
+			}
+
+			entry, ok := entries[strings.ToLower(key)].(map[string]any)
+			if !ok {
+				entry = map[string]any{}
+				entries[strings.ToLower(key)] = entry
+			}
+
+			setNested(entry, f.key(), value)
+
+			break
+		}
+	}
+
+	return entries
+}
+
+// defineCollectionFlags defines the flags setting the collections among fields: a flag named as the field, taking
+// the collection as JSON, and for lists of structs a flag per element field, as in --upstreams.host, given once per
+// element.
+func defineCollectionFlags(flagSet *pflag.FlagSet, fields []configField, n naming) {
+	for _, f := range fields {
+		name := f.flagName()
+		if name == "" || !f.collection() {
+			continue
+		}
+
+		flagSet.StringP(name, f.shorthand(), "", f.description())
+
+		if f.typ().Kind() != reflect.Slice {
+			continue
+		}
+
+		for _, elem := range elementFields(f, n) {
+			flagSet.StringArray(name+"."+elem.key(), nil, elem.description())
+
+			if elem.typ().Kind() == reflect.Bool {
+				flagSet.Lookup(name + "." + elem.key()).NoOptDefVal = "true"
+			}
+		}
+	}
+}
+
+// isCollectionFlag reports whether the flag named name is one of the flags defined by defineCollectionFlags, which
+// are read by collectionFlagMap rather than bound to keys.
+func isCollectionFlag(name string, fields []configField) bool {
+	for _, f := range fields {
+		if f.collection() && f.flagName() != "" && (name == f.flagName() || strings.HasPrefix(name, f.flagName()+".")) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// collectionFlagMap maps the flags setting the collections among fields to a nested map keyed by snout keys. The
+// n-th value of each element flag goes to the n-th element of the list.
+func collectionFlagMap(flagSet *pflag.FlagSet, fields []configField, n naming) (map[string]any, error) {
+	cfg := map[string]any{}
+
+	for _, f := range fields {
+		name := f.flagName()
+		if name == "" || !f.collection() {
+			continue
+		}
+
+		if flag := flagSet.Lookup(name); flag.Changed {
+			var doc any
+			if err := json.Unmarshal([]byte(flag.Value.String()), &doc); err != nil {
+				return nil, fmt.Errorf("flag --%s of %s is not valid JSON: %w", name, f.key(), err)
+			}
+
+			setNested(cfg, f.key(), doc)
+
+			continue
+		}
+
+		if f.typ().Kind() != reflect.Slice {
+			continue
+		}
+
+		var list []any
+
+		for _, elem := range elementFields(f, n) {
+			values, err := flagSet.GetStringArray(name + "." + elem.key())
+			if err != nil {
+				return nil, err
+			}
+
+			for i, value := range values {
+				if i == len(list) {
+					list = append(list, map[string]any{})
+				}
+
+				setNested(list[i].(map[string]any), elem.key(), value)
+			}
+		}
+
+		if len(list) > 0 {
+			setNested(cfg, f.key(), list)
+		}
+	}
+
+	return cfg, nil
+}
+
+// setElementDefaults sets the defaults of the element fields of the collections among fields into the elements held
+// by v that leave them unset, wherever the elements come from, as resolveVariants does for the fields of variants.
+func setElementDefaults(v *viper.Viper, fields []configField, n naming) {
+	for _, f := range fields {
+		if !f.collection() || f.variant() {
+			continue
+		}
+
+		elems := elementFields(f, n)
+		if len(elems) == 0 {
+			continue
+		}
+
+		// the collection is copied, as its elements may be shared with a source
+		value := copyNested(v.Get(f.key()))
+
+		var items []any
+
+		switch collection := value.(type) {
+		case []any:
+			items = collection
+		case map[string]any:
+			for _, entry := range collection {
+				items = append(items, entry)
+			}
+		default:
+			continue
+		}
+
+		changed := false
+
+		for _, item := range items {
+			elem, ok := item.(map[string]any)
+			if !ok {
+				continue
+			}
+
+			for _, ef := range elems {
+				if def, ok := ef.defaultValue(); ok && nestedValue(elem, ef.key()) == nil {
+					setNested(elem, ef.key(), def)
+					changed = true
+				}
+			}
+		}
+
+		if changed {
+			v.Set(f.key(), value)
+		}
+	}
+}
+
+// mergeConfigMap merges values, the nested maps of a configuration layer, into v. The lists of fields tagged
+// `merge:"append"` are appended to the lists of the layers merged before rather than replacing them. Maps are merged
+// key by key either way.
+func mergeConfigMap(v *viper.Viper, values map[string]any, fields []configField) error {
+	for _, f := range fields {
+		if !f.appends() {
+			continue
+		}
+
+		list, ok := nestedValue(values, f.key()).([]any)
+		if !ok {
+			continue
+		}
+
+		if current, ok := v.Get(f.key()).([]any); ok {
+			setNested(values, f.key(), append(slices.Clone(current), list...))
+		}
+	}
+
+	return v.MergeConfigMap(values)
+}
+
+// nestedValue returns the value stored in m under the dotted key, or nil.
+func nestedValue(m map[string]any, key string) any {
+	segments := strings.Split(key, ".")
+
+	for _, segment := range segments[:len(segments)-1] {
+		child, ok := m[segment].(map[string]any)
+		if !ok {
+			return nil
+		}
+
+		m = child
+	}
+
+	return m[segments[len(segments)-1]]
+}
@@ -0,0 +1,216 @@
+package snout_test
+
+import (
+	"context"
+	"strings"
+
+	"github.com/chiguirez/snout/v3"
+)
+
+type upstream struct {
+	Host string `snout:"host"`
+	Port int    `snout:"port"`
+	TLS  bool   `snout:"tls"`
+}
+
+type tenant struct {
+	DSN      string `snout:"dsn"`
+	PoolSize int    `snout:"pool_size"`
+}
+
+type collectionConfig struct {
+	Upstreams []upstream        `snout:"upstreams"`
+	Mirrors   []upstream        `snout:"mirrors" merge:"append"`
+	Tenants   map[string]tenant `snout:"tenants"`
+	Labels    map[string]string `snout:"labels"`
+}
+
+func (s *snoutSuite) TestCollections() {
+	bootstrap := func(vars map[string]string, opts ...snout.Options) (collectionConfig, error) {
+		cfgChan := make(chan collectionConfig, 1)
+
+		kernel := snout.Kernel[collectionConfig]{RunE: func(_ context.Context, config collectionConfig) error {
+			cfgChan <- config
+
+			return nil
+		}}
+
+		opts = append([]snout.Options{
+			snout.WithEnvVarPrefix("APP"),
+			snout.WithLookupEnv(func(name string) (string, bool) {
+				value, ok := vars[name]
+
+				return value, ok
+			}),
+			snout.WithEnviron(func() []string {
+				pairs := make([]string, 0, len(vars))
+				for name, value := range vars {
+					pairs = append(pairs, name+"="+value)
+				}
+
+				return pairs
+			}),
+		}, opts...)
+
+		if err := kernel.Bootstrap(context.TODO(), opts...).Initialize(); err != nil {
+			return collectionConfig{}, err
+		}
+
+		return <-cfgChan, nil
+	}
+
+	s.Run("Given a config Struct with lists of structs and maps", func() {
+		s.Run("When it is loaded from indexed and keyed env vars", func() {
+			cfg, err := bootstrap(map[string]string{
+				"APP_UPSTREAMS_0_HOST":       "a.internal",
+				"APP_UPSTREAMS_0_PORT":       "8080",
+				"APP_UPSTREAMS_1_HOST":       "b.internal",
+				"APP_UPSTREAMS_3_HOST":       "unreachable",
+				"APP_TENANTS_ACME_DSN":       "postgres://acme",
+				"APP_TENANTS_ACME_POOL_SIZE": "5",
+				"APP_TENANTS_GLOBEX_DSN":     "postgres://globex",
+				"APP_LABELS_TEAM":            "core",
+			}, snout.WithArgs())
+
+			s.Run("Then list elements are read by index until the first gap and map entries by key", func() {
+				s.Require().NoError(err)
+				s.Require().Equal([]upstream{{Host: "a.internal", Port: 8080}, {Host: "b.internal"}}, cfg.Upstreams)
+				s.Require().Equal(map[string]tenant{
+					"acme":   {DSN: "postgres://acme", PoolSize: 5},
+					"globex": {DSN: "postgres://globex"},
+				}, cfg.Tenants)
+				s.Require().Equal(map[string]string{"team": "core"}, cfg.Labels)
+			})
+		})
+
+		s.Run("When it is loaded from JSON env vars", func() {
+			cfg, err := bootstrap(map[string]string{
+				"APP_UPSTREAMS": `[{"host":"a.internal","port":8080,"tls":true}]`,
+				"APP_TENANTS":   `{"acme":{"dsn":"postgres://acme"}}`,
+			}, snout.WithArgs())
+
+			s.Run("Then the collections are decoded from the documents", func() {
+				s.Require().NoError(err)
+				s.Require().Equal([]upstream{{Host: "a.internal", Port: 8080, TLS: true}}, cfg.Upstreams)
+				s.Require().Equal(map[string]tenant{"acme": {DSN: "postgres://acme"}}, cfg.Tenants)
+			})
+		})
+
+		s.Run("When it is loaded from repeated and JSON flags", func() {
+			cfg, err := bootstrap(nil, snout.WithArgs(
+				"--upstreams.host=a.internal", "--upstreams.port=8080", "--upstreams.tls",
+				"--upstreams.host=b.internal", "--upstreams.port=9090",
+				`--labels={"team":"core"}`,
+			))
+
+			s.Run("Then the n-th value of each flag goes to the n-th element", func() {
+				s.Require().NoError(err)
+				s.Require().Equal([]upstream{
+					{Host: "a.internal", Port: 8080, TLS: true},
+					{Host: "b.internal", Port: 9090},
+				}, cfg.Upstreams)
+				s.Require().Equal(map[string]string{"team": "core"}, cfg.Labels)
+			})
+		})
+
+		s.Run("When a config file, env vars and flags all set the collections", func() {
+			cfg, err := bootstrap(map[string]string{
+				"APP_UPSTREAMS_0_HOST":   "env.internal",
+				"APP_MIRRORS_0_HOST":     "mirror-env",
+				"APP_TENANTS_GLOBEX_DSN": "postgres://globex",
+			},
+				snout.WithConfigReader(strings.NewReader(`
+upstreams:
+  - host: file.internal
+mirrors:
+  - host: mirror-file
+tenants:
+  acme:
+    dsn: postgres://acme
+`), snout.FormatYAML),
+				snout.WithArgs("--mirrors.host=mirror-flag"),
+			)
+
+			s.Run("Then lists are replaced unless tagged to be appended and maps are merged by key", func() {
+				s.Require().NoError(err)
+				s.Require().Equal([]upstream{{Host: "env.internal"}}, cfg.Upstreams)
+				s.Require().Equal([]upstream{{Host: "mirror-file"}, {Host: "mirror-env"}, {Host: "mirror-flag"}},
+					cfg.Mirrors)
+				s.Require().Equal(map[string]tenant{
+					"acme":   {DSN: "postgres://acme"},
+					"globex": {DSN: "postgres://globex"},
+				}, cfg.Tenants)
+			})
+		})
+
+		s.Run("When the env var of a collection is not JSON", func() {
+			_, err := bootstrap(map[string]string{"APP_UPSTREAMS": "a.internal"}, snout.WithArgs())
+
+			s.Run("Then loading fails naming the env var", func() {
+				s.Require().ErrorIs(err, snout.ErrConfig)
+				s.Require().ErrorContains(err, "env var APP_UPSTREAMS of upstreams is not valid JSON")
+			})
+		})
+	})
+}
+
+func (s *snoutSuite) TestCollectionElementDefaults() {
+	s.Run("Given lists and maps of structs with defaulted element fields", func() {
+		type backend struct {
+			Host string `snout:"host"`
+			Port int    `snout:"port" default:"80"`
+		}
+
+		type stubConfig struct {
+			Ups     []backend          `snout:"ups"`
+			Tenants map[string]backend `snout:"tenants"`
+		}
+
+		load := func(opts ...snout.Options) stubConfig {
+			cfgChan := make(chan stubConfig, 1)
+
+			kernel := snout.Kernel[stubConfig]{RunE: func(_ context.Context, config stubConfig) error {
+				cfgChan <- config
+
+				return nil
+			}}
+
+			s.Require().NoError(kernel.Bootstrap(context.TODO(), opts...).Initialize())
+
+			return <-cfgChan
+		}
+
+		for name, opts := range map[string][]snout.Options{
+			"a file": {
+				snout.WithArgs(),
+				snout.WithConfigReader(strings.NewReader("ups:\n  - host: a\n    port: 8080\n  - host: b\n"+
+					"tenants:\n  acme:\n    host: c\n"), snout.FormatYAML),
+			},
+			"env vars": {
+				snout.WithArgs(),
+				snout.WithEnvVarPrefix("APP"),
+				snout.WithLookupEnv(func(name string) (string, bool) {
+					value, ok := map[string]string{
+						"APP_UPS_0_HOST": "a", "APP_UPS_0_PORT": "8080", "APP_UPS_1_HOST": "b", "APP_TENANTS_ACME_HOST": "c",
+					}[name]
+
+					return value, ok
+				}),
+				snout.WithEnviron(func() []string { return []string{"APP_TENANTS_ACME_HOST=c"} }),
+			},
+			"flags": {
+				snout.WithArgs("--ups.host=a", "--ups.host=b", "--ups.port=8080",
+					`--tenants={"acme":{"host":"c"}}`),
+			},
+		} {
+			s.Run("When elements omitting the defaulted field are read from "+name, func() {
+				cfg := load(opts...)
+
+				s.Run("Then the element default applies", func() {
+					s.Require().Equal([]backend{{Host: "a", Port: 8080}, {Host: "b", Port: 80}}, cfg.Ups)
+					s.Require().Equal(map[string]backend{"acme": {Host: "c", Port: 80}}, cfg.Tenants)
+				})
+			})
+		}
+	})
+}
@@ -0,0 +1,147 @@
+package snout
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// WithConfigDir registers a directory holding one file per configuration key as a source in KernelOptions, as
+// Kubernetes mounts ConfigMaps and Secrets. Files such as kafka.topic or kafka/topic map to the key kafka.topic and
+// trailing newlines are trimmed. Kubernetes bookkeeping entries starting with .. are skipped while the ..data symlink
+// they point through is followed, so atomic updates are picked up on reload.
+func WithConfigDir(path string) Options {
+	return WithSource(configDir(path))
+}
+
+// configDir is a Source reading a directory with one file per configuration key.
+type configDir string
+
+// Load implements Source.
+func (d configDir) Load(context.Context) (map[string]any, error) {
+	values := map[string]any{}
+	if err := readConfigDir(string(d), "", values); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// Watch implements Watcher, calling onChange whenever the content of the directory changes.
+func (d configDir) Watch(ctx context.Context, onChange func()) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	if err := watchConfigDir(watcher, string(d)); err != nil {
+		return err
+	}
+
+	last := d.fingerprint(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-watcher.Errors:
+			return err
+		case event := <-watcher.Events:
+			if event.Has(fsnotify.Create) {
+				_ = watchConfigDir(watcher, event.Name)
+			}
+
+			if current := d.fingerprint(ctx); current != last {
+				last = current
+
+				onChange()
+			}
+		}
+	}
+}
+
+// fingerprint returns a canonical representation of the content of the directory, for change detection.
+func (d configDir) fingerprint(ctx context.Context) string {
+	values, err := d.Load(ctx)
+	if err != nil {
+		return ""
+	}
+
+	out, _ := json.Marshal(values)
+
+	return string(out)
+}
+
+// readConfigDir reads every file below dir into values, keyed by their path relative to the config dir joined with
+// dots and prefixed by prefix.
+func readConfigDir(dir, prefix string, values map[string]any) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), "..") {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		key := prefix + entry.Name()
+
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			if err := readConfigDir(path, key+".", values); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		values[key] = strings.TrimRight(string(content), "\r\n")
+	}
+
+	return nil
+}
+
+// watchConfigDir adds dir and the directories below it to watcher.
+func watchConfigDir(watcher *fsnotify.Watcher, dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil || !info.IsDir() {
+		return err
+	}
+
+	if err := watcher.Add(dir); err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), "..") {
+			continue
+		}
+
+		if err := watchConfigDir(watcher, filepath.Join(dir, entry.Name())); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
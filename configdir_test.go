@@ -0,0 +1,143 @@
+package snout_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/chiguirez/snout/v3"
+)
+
+type configDirConfig struct {
+	Kafka struct {
+		Topic   string `snout:"topic"`
+		Retries int    `snout:"retries"`
+	} `snout:"kafka"`
+}
+
+func (s *snoutSuite) TestConfigDir() {
+	s.Run("Given a directory with one file per key", func() {
+		dir := s.T().TempDir()
+
+		s.Require().NoError(os.WriteFile(filepath.Join(dir, "kafka.topic"), []byte("orders\n"), 0o600))
+		s.Require().NoError(os.Mkdir(filepath.Join(dir, "kafka"), 0o700))
+		s.Require().NoError(os.WriteFile(filepath.Join(dir, "kafka", "retries"), []byte("5\n"), 0o600))
+
+		s.Run("When Kernel is Initialized", func() {
+			cfgChan := make(chan configDirConfig, 1)
+
+			kernel := snout.Kernel[configDirConfig]{RunE: func(_ context.Context, config configDirConfig) error {
+				cfgChan <- config
+
+				return nil
+			}}
+
+			err := kernel.Bootstrap(context.TODO(), snout.WithConfigDir(dir)).Initialize()
+			s.Require().NoError(err)
+
+			s.Run("Then file names map to keys and trailing newlines are trimmed", func() {
+				config := <-cfgChan
+				s.Require().Equal("orders", config.Kafka.Topic)
+				s.Require().Equal(5, config.Kafka.Retries)
+			})
+		})
+	})
+}
+
+func (s *snoutSuite) TestConfigDirReload() {
+	s.Run("Given a directory laid out as a Kubernetes ConfigMap mount", func() {
+		dir := s.T().TempDir()
+
+		s.Require().NoError(os.Mkdir(filepath.Join(dir, "..2024_01"), 0o700))
+		s.Require().NoError(os.WriteFile(filepath.Join(dir, "..2024_01", "kafka.topic"), []byte("orders\n"), 0o600))
+		s.Require().NoError(os.Symlink("..2024_01", filepath.Join(dir, "..data")))
+		s.Require().NoError(os.Symlink(filepath.Join("..data", "kafka.topic"), filepath.Join(dir, "kafka.topic")))
+
+		// update atomically swaps the ConfigMap for a new version, the way the kubelet does.
+		update := func(version int, topic string) error {
+			data := fmt.Sprintf("..2024_%02d", version+1)
+
+			if err := os.Mkdir(filepath.Join(dir, data), 0o700); err != nil {
+				return err
+			}
+
+			if err := os.WriteFile(filepath.Join(dir, data, "kafka.topic"), []byte(topic+"\n"), 0o600); err != nil {
+				return err
+			}
+
+			if err := os.Symlink(data, filepath.Join(dir, "..data_tmp")); err != nil {
+				return err
+			}
+
+			return os.Rename(filepath.Join(dir, "..data_tmp"), filepath.Join(dir, "..data"))
+		}
+
+		s.Run("When the ConfigMap is atomically updated while the Kernel runs", func() {
+			started := make(chan configDirConfig, 1)
+			reloaded := make(chan configDirConfig, 1)
+
+			kernel := snout.Kernel[configDirConfig]{
+				RunE: func(ctx context.Context, config configDirConfig) error {
+					started <- config
+
+					select {
+					case <-ctx.Done():
+					case <-time.After(2 * time.Second):
+					}
+
+					return nil
+				},
+				OnReload: func(_ context.Context, config configDirConfig) error {
+					select {
+					case reloaded <- config:
+					default:
+					}
+
+					return nil
+				},
+			}
+
+			ctx, cancel := context.WithCancel(context.TODO())
+			defer cancel()
+
+			done := make(chan error, 1)
+
+			go func() {
+				done <- kernel.Bootstrap(ctx, snout.WithConfigDir(dir)).Initialize()
+			}()
+
+			s.Require().Equal("orders", (<-started).Kafka.Topic)
+
+			// The directory is watched along RunE, so an update landing before the watcher reads the directory goes
+			// unnoticed. Each attempt writes a new topic until one is reloaded.
+			var (
+				version int
+				config  configDirConfig
+			)
+
+			s.Require().Eventually(func() bool {
+				version++
+				if err := update(version, fmt.Sprintf("payments-%d", version)); err != nil {
+					return false
+				}
+
+				select {
+				case config = <-reloaded:
+					return true
+				case <-time.After(100 * time.Millisecond):
+					return false
+				}
+			}, 2*time.Second, time.Millisecond)
+
+			s.Run("Then the new configuration is handed to OnReload", func() {
+				s.Require().True(strings.HasPrefix(config.Kafka.Topic, "payments-"), config.Kafka.Topic)
+
+				cancel()
+				s.Require().NoError(<-done)
+			})
+		})
+	})
+}
@@ -0,0 +1,304 @@
+package snout
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+)
+
+const (
+	// FormatTOML reads TOML config files.
+	FormatTOML Format = "toml"
+	// FormatHCL reads HCL config files.
+	FormatHCL Format = "hcl"
+	// FormatINI reads INI config files, keys outside any section being top level keys.
+	FormatINI Format = "ini"
+)
+
+// ErrAmbiguousConfig is an error indicating several config files match the service name in the config folder.
+var ErrAmbiguousConfig = errors.New("ambiguous config file")
+
+// configExtensions lists the file extensions of every config file format.
+var configExtensions = map[Format][]string{
+	FormatYAML: {"yaml", "yml"},
+	FormatJSON: {"json"},
+	FormatTOML: {"toml"},
+	FormatHCL:  {"hcl", "tfvars"},
+	FormatINI:  {"ini"},
+	FormatEnv:  {"env", "dotenv"},
+}
+
+// configFormats lists the config file formats in discovery order.
+var configFormats = []Format{FormatYAML, FormatJSON, FormatTOML, FormatHCL, FormatINI, FormatEnv}
+
+// WithConfigFile sets the path of the config file in KernelOptions, instead of looking for one named after the
+// service in the config folder. Its format is taken from the extension unless set with WithConfigFormat.
+func WithConfigFile(path string) Options {
+	return func(kernel *KernelOptions) {
+		kernel.Env.ConfigFile = path
+	}
+}
+
+// WithConfigReader sets a reader holding the config document, in the given format, in KernelOptions. It replaces
+// any config file, e.g. to read the configuration from os.Stdin or from an in-memory document in tests. The document
+// is read once and kept for reloads.
+func WithConfigReader(r io.Reader, format Format) Options {
+	return func(kernel *KernelOptions) {
+		kernel.Env.ConfigReader = r
+		kernel.Env.ConfigFormat = format
+	}
+}
+
+// WithConfigFormat sets the format of the config file in KernelOptions. When looking for the config file in the
+// config folder, only files with an extension of that format are considered.
+func WithConfigFormat(format Format) Options {
+	return func(kernel *KernelOptions) {
+		kernel.Env.ConfigFormat = format
+	}
+}
+
+// readConfigDocument reads the config document set with WithConfigReader, if any, into options once, before the
+// configuration is first loaded.
+func readConfigDocument(options *KernelOptions) error {
+	if options.Env.ConfigReader == nil {
+		return nil
+	}
+
+	document, err := io.ReadAll(options.Env.ConfigReader)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrConfig, err)
+	}
+
+	options.configDocument = document
+
+	return nil
+}
+
+// readConfig reads the config document set with WithConfigReader, or else the config file, into nested maps, with
+// the files it includes merged in. The document of a reader, read by readConfigDocument, is read again on every
+// reload, and includes files relative to the config folder.
+func readConfig(options *KernelOptions) (map[string]any, error) {
+	if options.Env.ConfigReader != nil {
+		values, err := readConfigReader(bytes.NewReader(options.configDocument), options.Env.ConfigFormat)
+		if err != nil {
+			return nil, err
+		}
+
+		return resolveIncludes(values, options.Env.VarFile, options.Env.VarFile, nil)
+	}
+
+	path, err := findConfigFile(options)
+	if err != nil || path == "" {
+		return nil, err
+	}
+
+	values, err := readConfigFile(path, options.Env.ConfigFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err = resolveIncludes(values, filepath.Dir(abs), options.Env.VarFile, []string{abs})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	logger.Info("Using config file", slog.String("config file", path))
+
+	return values, nil
+}
+
+// findConfigFile returns the path of the config file to read, or "" when there is none.
+func findConfigFile(options *KernelOptions) (string, error) {
+	if options.Env.ConfigFile != "" {
+		return options.Env.ConfigFile, nil
+	}
+
+	name := options.ServiceName
+	if name == "" {
+		name = "config"
+	}
+
+	formats := configFormats
+	if options.Env.ConfigFormat != "" {
+		formats = []Format{options.Env.ConfigFormat}
+	}
+
+	var candidates []string
+
+	for _, format := range formats {
+		for _, ext := range configExtensions[format] {
+			path := filepath.Join(options.Env.VarFile, name+"."+ext)
+			if info, err := os.Stat(path); err == nil && !info.IsDir() {
+				candidates = append(candidates, path)
+			}
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return "", nil
+	case 1:
+		return candidates[0], nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrAmbiguousConfig, strings.Join(candidates, ", "))
+	}
+}
+
+// readConfigFile reads the config file at path into nested maps.
+func readConfigFile(path string, format Format) (map[string]any, error) {
+	if format == "" {
+		format = formatOf(path)
+	}
+
+	fv := viper.New()
+	fv.SetConfigFile(path)
+
+	if format != "" {
+		fv.SetConfigType(string(format))
+	}
+
+	if err := fv.ReadInConfig(); err != nil {
+		return nil, err
+	}
+
+	return normalizeConfig(fv.AllSettings(), format), nil
+}
+
+// readConfigReader reads a config document in the given format into nested maps, normalized as readConfigFile does.
+func readConfigReader(r io.Reader, format Format) (map[string]any, error) {
+	if format == "" {
+		return nil, fmt.Errorf("%w: config reader requires a format", ErrUnknownFormat)
+	}
+
+	fv := viper.New()
+	fv.SetConfigType(string(format))
+
+	if err := fv.ReadConfig(r); err != nil {
+		return nil, err
+	}
+
+	return normalizeConfig(fv.AllSettings(), format), nil
+}
+
+// normalizeConfig rewrites the shapes the HCL and INI decoders produce so that every format maps to the same keys.
+func normalizeConfig(values map[string]any, format Format) map[string]any {
+	switch format {
+	case FormatHCL:
+		values, _ = unwrapBlocks(values).(map[string]any)
+	case FormatINI:
+		if section, ok := values["default"].(map[string]any); ok {
+			delete(values, "default")
+
+			for key, value := range section {
+				values[key] = value
+			}
+		}
+	}
+
+	return values
+}
+
+// formatOf returns the format of a config file after its extension, or "" when unknown.
+func formatOf(path string) Format {
+	ext := strings.TrimPrefix(filepath.Ext(path), ".")
+
+	for format, exts := range configExtensions {
+		for _, candidate := range exts {
+			if strings.EqualFold(ext, candidate) {
+				return format
+			}
+		}
+	}
+
+	return ""
+}
+
+// unwrapBlocks replaces the single element lists of maps HCL decodes blocks into with the map itself.
+func unwrapBlocks(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, child := range v {
+			v[key] = unwrapBlocks(child)
+		}
+
+		return v
+	case []map[string]any:
+		if len(v) == 1 {
+			return unwrapBlocks(v[0])
+		}
+
+		items := make([]any, 0, len(v))
+		for _, item := range v {
+			items = append(items, unwrapBlocks(item))
+		}
+
+		return items
+	default:
+		return value
+	}
+}
+
+// watchConfigFile calls onChange whenever the content of the config file changes, until ctx is done. The folder
+// holding the file is watched rather than the file itself, so that files replaced by editors or through Kubernetes
+// ConfigMap symlinks are noticed too. There is nothing to watch for a config document read from a reader.
+func watchConfigFile(ctx context.Context, options *KernelOptions, onChange func()) error {
+	if options.Env.ConfigReader != nil {
+		return nil
+	}
+
+	path, err := findConfigFile(options)
+	if err != nil || path == "" {
+		return err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		return err
+	}
+
+	last := fileFingerprint(path)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-watcher.Errors:
+			return err
+		case <-watcher.Events:
+			if current := fileFingerprint(path); current != last {
+				last = current
+
+				onChange()
+			}
+		}
+	}
+}
+
+// fileFingerprint returns the content of the file at path, for change detection.
+func fileFingerprint(path string) string {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+
+	return string(content)
+}
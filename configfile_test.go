@@ -0,0 +1,141 @@
+package snout_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/chiguirez/snout/v3"
+)
+
+type formatConfig struct {
+	A string `snout:"a"`
+	B int    `snout:"b"`
+	C bool   `snout:"c"`
+	D *struct {
+		A *string  `snout:"a"`
+		B *float64 `snout:"b"`
+		C *bool    `snout:"c"`
+	} `snout:"d"`
+	E time.Duration `snout:"e"`
+}
+
+func (s *snoutSuite) TestConfigFileFormats() {
+	for _, name := range []string{"TOML", "HCL", "INI"} {
+		s.Run("Given a config Struct with snout tags and a "+name+" file", func() {
+			s.Run("When Kernel is Initialized", func() {
+				cfgChan := make(chan formatConfig, 1)
+
+				kernel := snout.Kernel[formatConfig]{RunE: func(_ context.Context, config formatConfig) error {
+					cfgChan <- config
+
+					return nil
+				}}
+
+				err := kernel.Bootstrap(
+					context.TODO(),
+					snout.WithServiceName(name),
+					snout.WithEnvVarFolderLocation("./testdata/"),
+				).Initialize()
+				s.Require().NoError(err)
+
+				s.Run("Then all values are present", func() {
+					config := <-cfgChan
+					s.Require().Equal("a", config.A)
+					s.Require().Equal(1, config.B)
+					s.Require().True(config.C)
+					s.Require().Equal("da", *config.D.A)
+					s.Require().Equal(3.1415, *config.D.B)
+					s.Require().False(*config.D.C)
+					s.Require().Equal(30*time.Minute, config.E)
+				})
+			})
+		})
+	}
+}
+
+func (s *snoutSuite) TestConfigFileWithFormat() {
+	s.Run("Given a config file whose extension doesn't tell its format", func() {
+		s.Run("When Kernel is Initialized with the file and its format", func() {
+			cfgChan := make(chan formatConfig, 1)
+
+			kernel := snout.Kernel[formatConfig]{RunE: func(_ context.Context, config formatConfig) error {
+				cfgChan <- config
+
+				return nil
+			}}
+
+			err := kernel.Bootstrap(
+				context.TODO(),
+				snout.WithConfigFile("./testdata/ambiguous/settings.conf"),
+				snout.WithConfigFormat(snout.FormatTOML),
+			).Initialize()
+			s.Require().NoError(err)
+
+			s.Run("Then values are read from that file", func() {
+				config := <-cfgChan
+				s.Require().Equal("a", config.A)
+				s.Require().Equal(3.1415, *config.D.B)
+			})
+		})
+	})
+}
+
+func (s *snoutSuite) TestConfigFileMissing() {
+	s.Run("Given an explicit config file that doesn't exist", func() {
+		s.Run("When the configuration is Checked", func() {
+			kernel := snout.Kernel[formatConfig]{}
+
+			err := kernel.Bootstrap(context.TODO(), snout.WithConfigFile("./testdata/missing.yaml")).Check()
+
+			s.Run("Then a config error is returned", func() {
+				s.Require().ErrorIs(err, snout.ErrConfig)
+			})
+		})
+	})
+}
+
+func (s *snoutSuite) TestAmbiguousConfigFile() {
+	s.Run("Given a config folder with a YAML and a JSON file for the service", func() {
+		type stubConfig struct {
+			A string `snout:"a"`
+		}
+
+		s.Run("When the configuration is Checked", func() {
+			kernel := snout.Kernel[stubConfig]{}
+
+			err := kernel.Bootstrap(
+				context.TODO(),
+				snout.WithServiceName("SVC"),
+				snout.WithEnvVarFolderLocation("./testdata/ambiguous/"),
+			).Check()
+
+			s.Run("Then an error listing both files is returned", func() {
+				s.Require().ErrorIs(err, snout.ErrConfig)
+				s.Require().ErrorIs(err, snout.ErrAmbiguousConfig)
+				s.Require().ErrorContains(err, "testdata/ambiguous/SVC.yaml, testdata/ambiguous/SVC.json")
+			})
+		})
+
+		s.Run("When Kernel is Initialized with a format", func() {
+			cfgChan := make(chan stubConfig, 1)
+
+			kernel := snout.Kernel[stubConfig]{RunE: func(_ context.Context, config stubConfig) error {
+				cfgChan <- config
+
+				return nil
+			}}
+
+			err := kernel.Bootstrap(
+				context.TODO(),
+				snout.WithServiceName("SVC"),
+				snout.WithEnvVarFolderLocation("./testdata/ambiguous/"),
+				snout.WithConfigFormat(snout.FormatJSON),
+			).Initialize()
+			s.Require().NoError(err)
+
+			s.Run("Then only files of that format are considered", func() {
+				s.Require().Equal("from-json", (<-cfgChan).A)
+			})
+		})
+	})
+}
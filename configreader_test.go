@@ -0,0 +1,129 @@
+package snout_test
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/chiguirez/snout/v3"
+)
+
+func (s *snoutSuite) TestConfigReader() {
+	s.Run("Given a config Struct with snout tags and an in-memory YAML document", func() {
+		document := `
+a: a
+b: 1
+c: true
+d:
+  a: da
+  b: 3.1415
+  c: false
+e: 30m
+`
+
+		s.Run("When Kernel is Initialized with the reader", func() {
+			cfgChan := make(chan formatConfig, 1)
+
+			kernel := snout.Kernel[formatConfig]{RunE: func(_ context.Context, config formatConfig) error {
+				cfgChan <- config
+
+				return nil
+			}}
+
+			err := kernel.Bootstrap(
+				context.TODO(),
+				snout.WithServiceName("SVC"),
+				snout.WithEnvVarFolderLocation("./testdata/ambiguous/"),
+				snout.WithConfigReader(strings.NewReader(document), snout.FormatYAML),
+			).Initialize()
+			s.Require().NoError(err)
+
+			s.Run("Then values come from the document instead of the config folder", func() {
+				config := <-cfgChan
+				s.Require().Equal("a", config.A)
+				s.Require().Equal(1, config.B)
+				s.Require().True(config.C)
+				s.Require().Equal("da", *config.D.A)
+				s.Require().Equal(3.1415, *config.D.B)
+				s.Require().False(*config.D.C)
+				s.Require().Equal(30*time.Minute, config.E)
+			})
+		})
+	})
+}
+
+func (s *snoutSuite) TestConfigReaderWithoutFormat() {
+	s.Run("Given an in-memory document without format", func() {
+		s.Run("When the configuration is Checked", func() {
+			kernel := snout.Kernel[formatConfig]{}
+
+			err := kernel.Bootstrap(context.TODO(), snout.WithConfigReader(strings.NewReader("a: a"), "")).Check()
+
+			s.Run("Then an unknown format error is returned", func() {
+				s.Require().ErrorIs(err, snout.ErrConfig)
+				s.Require().ErrorIs(err, snout.ErrUnknownFormat)
+			})
+		})
+	})
+}
+
+// changingSource is a source whose Watch reports a change every millisecond until ctx is done.
+type changingSource struct{}
+
+func (changingSource) Load(context.Context) (map[string]any, error) {
+	return map[string]any{}, nil
+}
+
+func (changingSource) Watch(ctx context.Context, onChange func()) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Millisecond):
+			onChange()
+		}
+	}
+}
+
+func (s *snoutSuite) TestConfigReaderReload() {
+	s.Run("Given an in-memory YAML document and a watched source", func() {
+		type stubConfig struct {
+			A string `snout:"a"`
+		}
+
+		s.Run("When the Kernel reloads while running", func() {
+			reloaded := make(chan stubConfig, 1)
+
+			kernel := snout.Kernel[stubConfig]{
+				RunE: func(ctx context.Context, _ stubConfig) error {
+					select {
+					case <-ctx.Done():
+					case <-time.After(100 * time.Millisecond):
+					}
+
+					return nil
+				},
+				OnReload: func(_ context.Context, config stubConfig) error {
+					select {
+					case reloaded <- config:
+					default:
+					}
+
+					return nil
+				},
+			}
+
+			err := kernel.Bootstrap(
+				context.TODO(),
+				snout.WithArgs(),
+				snout.WithConfigReader(strings.NewReader("a: a"), snout.FormatYAML),
+				snout.WithSource(changingSource{}),
+			).Initialize()
+			s.Require().NoError(err)
+
+			s.Run("Then every reload reads the document again", func() {
+				s.Require().Equal("a", (<-reloaded).A)
+			})
+		})
+	})
+}
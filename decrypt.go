@@ -0,0 +1,116 @@
+package snout
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// ErrDecryption is an error indicating an encrypted config value could not be decrypted.
+var ErrDecryption = errors.New("decryption error")
+
+// Decryptor decrypts config values encrypted in place, written as ENC[ciphertext] in config files, sources or env
+// vars. It is handed the ciphertext found between the brackets.
+type Decryptor interface {
+	Decrypt(ciphertext string) (string, error)
+}
+
+// WithDecryptor sets the decryptor of encrypted config values in KernelOptions.
+func WithDecryptor(decryptor Decryptor) Options {
+	return func(kernel *KernelOptions) {
+		kernel.Decryptor = decryptor
+	}
+}
+
+// decryptValues decrypts the ENC[...] values of every field, including those within the lists and maps of
+// collections and variant sections, and stores the plaintext back into v. It returns the keys of the fields holding
+// any decrypted value.
+func decryptValues(v *viper.Viper, fields []configField, decryptor Decryptor) (map[string]bool, error) {
+	decrypted := map[string]bool{}
+
+	for _, f := range fields {
+		value, changed, err := decryptValue(v.Get(f.key()), f.key(), decryptor)
+		if err != nil {
+			return nil, err
+		}
+
+		if changed {
+			v.Set(f.key(), value)
+			decrypted[f.key()] = true
+		}
+	}
+
+	return decrypted, nil
+}
+
+// decryptValue returns a copy of value, found under key, with its ENC[...] strings decrypted at any depth, reporting
+// whether any was.
+func decryptValue(value any, key string, decryptor Decryptor) (any, bool, error) {
+	switch v := value.(type) {
+	case string:
+		ciphertext, ok := encryptedValue(v)
+		if !ok {
+			return value, false, nil
+		}
+
+		if decryptor == nil {
+			return nil, false, fmt.Errorf("%w: %s: encrypted value found but no decryptor registered", ErrDecryption, key)
+		}
+
+		plaintext, err := decryptor.Decrypt(ciphertext)
+		if err != nil {
+			return nil, false, fmt.Errorf("%w: %s: %w", ErrDecryption, key, err)
+		}
+
+		return plaintext, true, nil
+	case []string:
+		items := make([]any, len(v))
+		for i, item := range v {
+			items[i] = item
+		}
+
+		return decryptValue(items, key, decryptor)
+	case []any:
+		items := make([]any, len(v))
+		changed := false
+
+		for i, item := range v {
+			decrypted, ok, err := decryptValue(item, fmt.Sprintf("%s[%d]", key, i), decryptor)
+			if err != nil {
+				return nil, false, err
+			}
+
+			items[i], changed = decrypted, changed || ok
+		}
+
+		return items, changed, nil
+	case map[string]any:
+		entries := make(map[string]any, len(v))
+		changed := false
+
+		for k, entry := range v {
+			decrypted, ok, err := decryptValue(entry, key+"."+k, decryptor)
+			if err != nil {
+				return nil, false, err
+			}
+
+			entries[k], changed = decrypted, changed || ok
+		}
+
+		return entries, changed, nil
+	default:
+		return value, false, nil
+	}
+}
+
+// encryptedValue returns the ciphertext of an ENC[...] value, reporting whether value is one.
+func encryptedValue(value string) (string, bool) {
+	value = strings.TrimSpace(value)
+	if !strings.HasPrefix(value, "ENC[") || !strings.HasSuffix(value, "]") {
+		return "", false
+	}
+
+	return value[len("ENC[") : len(value)-1], true
+}
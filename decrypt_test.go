@@ -0,0 +1,144 @@
+package snout_test
+
+import (
+	"context"
+	"strings"
+
+	"github.com/chiguirez/snout/v3"
+)
+
+type reverseDecryptor struct{}
+
+func (reverseDecryptor) Decrypt(ciphertext string) (string, error) {
+	runes := []rune(ciphertext)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+
+	return string(runes), nil
+}
+
+func (s *snoutSuite) TestDecryptor() {
+	s.Run("Given encrypted values in a config file and an env var", func() {
+		type stubConfig struct {
+			User     string `snout:"user"`
+			Password string `snout:"password"`
+			Token    string `snout:"token"`
+		}
+
+		s.T().Setenv("DECRYPT_TOKEN", "ENC[nekot]")
+
+		s.Run("When Kernel is Initialized with a Decryptor", func() {
+			cfgChan := make(chan stubConfig, 1)
+
+			kernel := snout.Kernel[stubConfig]{RunE: func(_ context.Context, config stubConfig) error {
+				cfgChan <- config
+
+				return nil
+			}}
+
+			err := kernel.Bootstrap(
+				context.TODO(),
+				snout.WithEnvVarPrefix("DECRYPT"),
+				snout.WithConfigReader(strings.NewReader("user: app\npassword: ENC[2retnuh]\n"), snout.FormatYAML),
+				snout.WithDecryptor(reverseDecryptor{}),
+			).Initialize()
+			s.Require().NoError(err)
+
+			s.Run("Then encrypted values are decrypted wherever they come from", func() {
+				config := <-cfgChan
+				s.Require().Equal("app", config.User)
+				s.Require().Equal("hunter2", config.Password)
+				s.Require().Equal("token", config.Token)
+			})
+		})
+	})
+}
+
+func (s *snoutSuite) TestEncryptedValueWithoutDecryptor() {
+	s.Run("Given an encrypted value in a config file", func() {
+		type stubConfig struct {
+			Password string `snout:"password"`
+		}
+
+		s.Run("When the configuration is Checked without Decryptor", func() {
+			kernel := snout.Kernel[stubConfig]{}
+
+			err := kernel.Bootstrap(
+				context.TODO(),
+				snout.WithConfigReader(strings.NewReader("password: ENC[2retnuh]\n"), snout.FormatYAML),
+			).Check()
+
+			s.Run("Then a decryption error naming the key is returned", func() {
+				s.Require().ErrorIs(err, snout.ErrConfig)
+				s.Require().ErrorIs(err, snout.ErrDecryption)
+				s.Require().ErrorContains(err, "password: encrypted value found but no decryptor registered")
+			})
+		})
+	})
+}
+
+func (s *snoutSuite) TestDecryptCollections() {
+	s.Run("Given encrypted values within lists, maps and variant sections", func() {
+		type stubConfig struct {
+			Keys      []string                `snout:"keys"`
+			Upstreams []dumpUpstream          `snout:"upstreams"`
+			Tenants   map[string]dumpUpstream `snout:"tenants"`
+			Queue     queue                   `snout:"queue"`
+		}
+
+		doc := `
+keys: ["ENC[1yek]", plain]
+upstreams:
+  - host: a
+    credentials: {password: "ENC[2retnuh]"}
+tenants:
+  acme:
+    credentials: {password: "ENC[3retnuh]"}
+queue: {type: sqs, token: "ENC[4retnuh]"}
+`
+
+		s.Run("When Kernel is Initialized with a Decryptor", func() {
+			cfgChan := make(chan stubConfig, 1)
+
+			kernel := snout.Kernel[stubConfig]{RunE: func(_ context.Context, config stubConfig) error {
+				cfgChan <- config
+
+				return nil
+			}}
+
+			err := kernel.Bootstrap(
+				context.TODO(),
+				snout.WithConfigReader(strings.NewReader(doc), snout.FormatYAML),
+				snout.WithVariant[queue]("sqs", &dumpQueue{}),
+				snout.WithDecryptor(reverseDecryptor{}),
+				snout.WithArgs(),
+			).Initialize()
+			s.Require().NoError(err)
+
+			s.Run("Then they are decrypted at any depth", func() {
+				config := <-cfgChan
+				s.Require().Equal([]string{"key1", "plain"}, config.Keys)
+				s.Require().Equal("hunter2", config.Upstreams[0].Credentials.Password)
+				s.Require().Equal("hunter3", config.Tenants["acme"].Credentials.Password)
+				s.Require().Equal(&dumpQueue{Token: "hunter4"}, config.Queue)
+			})
+		})
+
+		s.Run("When the configuration is Checked without Decryptor", func() {
+			kernel := snout.Kernel[stubConfig]{}
+
+			err := kernel.Bootstrap(
+				context.TODO(),
+				snout.WithConfigReader(strings.NewReader(doc), snout.FormatYAML),
+				snout.WithVariant[queue]("sqs", &dumpQueue{}),
+				snout.WithArgs(),
+			).Check()
+
+			s.Run("Then a decryption error naming the first nested key is returned", func() {
+				s.Require().ErrorIs(err, snout.ErrDecryption)
+				s.Require().ErrorContains(err, "keys[0]: encrypted value found but no decryptor registered")
+			})
+		})
+	})
+}
@@ -0,0 +1,368 @@
+package snout
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// loadDotEnvFiles reads the given dotenv files in order, later files overriding earlier ones. Missing files are
+// skipped. References to other variables are expanded from lookup first and then from the values read so far.
+func loadDotEnvFiles(paths []string, lookup func(string) (string, bool)) (map[string]string, error) {
+	values := map[string]string{}
+
+	for _, path := range paths {
+		src, err := os.ReadFile(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if err := parseDotEnv(string(src), lookup, values); err != nil {
+			return nil, fmt.Errorf("%s:%w", path, err)
+		}
+
+		logger.Info("Using dotenv file", slog.String("dotenv file", path))
+	}
+
+	return values, nil
+}
+
+// envConfigMap maps the environment variables bound to the given fields, as resolved by lookup, to a nested map
+// keyed by snout keys. Fields excluded from env vars are skipped, and collections are read by collectionEnvMap. The
+// env var of a list holding a JSON array, as in APP_TAGS='["a","b"]', sets its items, and any other value a single
+// item.
+func envConfigMap(fields []configField, env Env, lookup func(string) (string, bool)) map[string]any {
+	cfg := map[string]any{}
+
+	for _, f := range fields {
+		name := f.envName(env)
+		if name == "" || f.collection() {
+			continue
+		}
+
+		value, ok := lookup(name)
+		if !ok {
+			continue
+		}
+
+		var items []any
+		if t := indirectType(f.typ()); t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8 &&
+			json.Unmarshal([]byte(value), &items) == nil {
+			setNested(cfg, f.key(), items)
+
+			continue
+		}
+
+		setNested(cfg, f.key(), value)
+	}
+
+	return cfg
+}
+
+// setNested stores value in m under the dotted key, creating intermediate maps as needed.
+func setNested(m map[string]any, key string, value any) {
+	segments := strings.Split(key, ".")
+
+	for _, segment := range segments[:len(segments)-1] {
+		child, ok := m[segment].(map[string]any)
+		if !ok {
+			child = map[string]any{}
+			m[segment] = child
+		}
+
+		m = child
+	}
+
+	m[segments[len(segments)-1]] = value
+}
+
+// dotEnvParser parses a dotenv document.
+type dotEnvParser struct {
+	src    string
+	pos    int
+	line   int
+	lookup func(string) (string, bool)
+	values map[string]string
+}
+
+// parseDotEnv parses a dotenv document, storing its variables into values.
+//
+// Lines hold NAME=value pairs, optionally preceded by export, and # starts a comment. Values may be unquoted,
+// 'single quoted' to be taken literally or "double quoted" to allow \n, \t, \", \\ and \$ escapes; quoted values may
+// span several lines. Unquoted and double quoted values expand $NAME and ${NAME} references through lookup, falling
+// back to variables already held by values.
+func parseDotEnv(src string, lookup func(string) (string, bool), values map[string]string) error {
+	p := &dotEnvParser{src: src, line: 1, lookup: lookup, values: values}
+
+	for {
+		p.skipBlank()
+
+		if p.eof() {
+			return nil
+		}
+
+		line := p.line
+		if err := p.parseLine(); err != nil {
+			return fmt.Errorf("%d: %w", line, err)
+		}
+	}
+}
+
+// parseLine parses a single NAME=value assignment.
+func (p *dotEnvParser) parseLine() error {
+	name := p.readName()
+	if name == "export" && p.peek() != '=' {
+		p.skipSpaces()
+		name = p.readName()
+	}
+
+	if name == "" {
+		return fmt.Errorf("expected a variable name, found %q", p.peek())
+	}
+
+	p.skipSpaces()
+
+	if p.peek() != '=' {
+		return fmt.Errorf("expected '=' after %s", name)
+	}
+
+	p.pos++
+	p.skipSpaces()
+
+	var (
+		value string
+		err   error
+	)
+
+	switch p.peek() {
+	case '\'':
+		value, err = p.readSingleQuoted()
+	case '"':
+		value, err = p.readDoubleQuoted()
+	default:
+		value = p.readUnquoted()
+	}
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+
+	p.skipSpaces()
+
+	if !p.eof() && p.peek() != '\n' && p.peek() != '#' {
+		return fmt.Errorf("%s: unexpected %q after value", name, p.peek())
+	}
+
+	p.skipLine()
+	p.values[name] = value
+
+	return nil
+}
+
+// readName reads a variable name.
+func (p *dotEnvParser) readName() string {
+	start := p.pos
+	for !p.eof() && isNameChar(p.peek()) {
+		p.pos++
+	}
+
+	return p.src[start:p.pos]
+}
+
+// readSingleQuoted reads a literal value up to the closing quote.
+func (p *dotEnvParser) readSingleQuoted() (string, error) {
+	p.pos++
+
+	end := strings.IndexByte(p.src[p.pos:], '\'')
+	if end < 0 {
+		return "", errors.New("unterminated single quoted value")
+	}
+
+	value := p.src[p.pos : p.pos+end]
+	p.line += strings.Count(value, "\n")
+	p.pos += end + 1
+
+	return value, nil
+}
+
+// readDoubleQuoted reads a value up to the closing quote, resolving escapes and variable references.
+func (p *dotEnvParser) readDoubleQuoted() (string, error) {
+	var value strings.Builder
+
+	p.pos++
+
+	for !p.eof() {
+		c := p.src[p.pos]
+
+		switch c {
+		case '"':
+			p.pos++
+
+			return value.String(), nil
+		case '\\':
+			p.pos++
+			if p.eof() {
+				return "", errors.New("unterminated double quoted value")
+			}
+
+			value.WriteString(unescape(p.src[p.pos]))
+			p.pos++
+		case '$':
+			value.WriteString(p.readReference())
+		default:
+			if c == '\n' {
+				p.line++
+			}
+
+			value.WriteByte(c)
+			p.pos++
+		}
+	}
+
+	return "", errors.New("unterminated double quoted value")
+}
+
+// readUnquoted reads a value up to the end of the line or an inline comment, resolving variable references.
+func (p *dotEnvParser) readUnquoted() string {
+	var value strings.Builder
+
+	for !p.eof() && p.peek() != '\n' {
+		c := p.peek()
+		if c == '#' && (value.Len() == 0 || isSpace(p.src[p.pos-1])) {
+			break
+		}
+
+		if c == '$' {
+			value.WriteString(p.readReference())
+
+			continue
+		}
+
+		value.WriteByte(c)
+		p.pos++
+	}
+
+	return strings.TrimRight(value.String(), " \t\r")
+}
+
+// readReference reads a $NAME or ${NAME} reference and returns its value, or the text as is when it isn't one.
+func (p *dotEnvParser) readReference() string {
+	start := p.pos
+	p.pos++
+
+	braced := p.peek() == '{'
+	if braced {
+		p.pos++
+	}
+
+	refStart := p.pos
+	for !p.eof() && isNameChar(p.peek()) && p.peek() != '.' {
+		p.pos++
+	}
+
+	name := p.src[refStart:p.pos]
+
+	if braced {
+		if p.peek() != '}' {
+			p.pos = start + 1
+
+			return "$"
+		}
+
+		p.pos++
+	}
+
+	if name == "" {
+		return p.src[start:p.pos]
+	}
+
+	if value, ok := p.lookup(name); ok {
+		return value
+	}
+
+	return p.values[name]
+}
+
+// skipBlank skips whitespace, empty lines and comment lines.
+func (p *dotEnvParser) skipBlank() {
+	for !p.eof() {
+		switch c := p.peek(); {
+		case c == '\n':
+			p.line++
+			p.pos++
+		case isSpace(c):
+			p.pos++
+		case c == '#':
+			p.skipLine()
+		default:
+			return
+		}
+	}
+}
+
+// skipSpaces skips spaces and tabs within the current line.
+func (p *dotEnvParser) skipSpaces() {
+	for !p.eof() && isSpace(p.peek()) {
+		p.pos++
+	}
+}
+
+// skipLine skips the rest of the current line, including its line break.
+func (p *dotEnvParser) skipLine() {
+	end := strings.IndexByte(p.src[p.pos:], '\n')
+	if end < 0 {
+		p.pos = len(p.src)
+
+		return
+	}
+
+	p.pos += end + 1
+	p.line++
+}
+
+// peek returns the current byte, or 0 at the end of the document.
+func (p *dotEnvParser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+
+	return p.src[p.pos]
+}
+
+// eof reports whether the whole document has been read.
+func (p *dotEnvParser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+// unescape resolves the character following a backslash in a double quoted value.
+func unescape(c byte) string {
+	switch c {
+	case 'n':
+		return "\n"
+	case 'r':
+		return "\r"
+	case 't':
+		return "\t"
+	default:
+		return string(c)
+	}
+}
+
+// isNameChar reports whether c can be part of a variable name.
+func isNameChar(c byte) bool {
+	return c == '_' || c == '.' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// isSpace reports whether c is a space within a line.
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r'
+}
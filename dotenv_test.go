@@ -0,0 +1,76 @@
+package snout_test
+
+import (
+	"context"
+	"os"
+
+	"github.com/chiguirez/snout/v3"
+)
+
+func (s *snoutSuite) TestDotEnvFiles() {
+	s.Run("Given a config Struct with snout tags and dotenv files", func() {
+		type stubConfig struct {
+			A string `snout:"a"`
+			B int    `snout:"b"`
+			C bool   `snout:"c"`
+			D *struct {
+				A *string  `snout:"a"`
+				B *float64 `snout:"b"`
+				C *bool    `snout:"c"`
+			} `snout:"d"`
+			M string `snout:"m"`
+		}
+
+		s.T().Setenv("DOTENV_SUFFIX", "env")
+		s.T().Setenv("DOTENV_D_C", "true")
+
+		s.Run("When Kernel is Initialized with Prefix", func() {
+			cfgChan := make(chan stubConfig, 1)
+
+			kernel := snout.Kernel[stubConfig]{RunE: func(_ context.Context, config stubConfig) error {
+				cfgChan <- config
+
+				return nil
+			}}
+
+			err := kernel.Bootstrap(
+				context.TODO(),
+				snout.WithEnvVarPrefix("DOTENV"),
+				snout.WithDotEnvFiles("./testdata/dotenv/.env", "./testdata/dotenv/.env.local", "./testdata/dotenv/.env.missing"),
+			).Initialize()
+			s.Require().NoError(err)
+
+			s.Run("Then values are read, later files override earlier ones and process env wins", func() {
+				config := <-cfgChan
+				s.Require().Equal("a", config.A)
+				s.Require().Equal(1, config.B)
+				s.Require().True(config.C)
+				s.Require().Equal("a-env", *config.D.A)
+				s.Require().Equal(2.5, *config.D.B)
+				s.Require().True(*config.D.C)
+				s.Require().Equal("line one\nline two\twith tab", config.M)
+			})
+		})
+	})
+}
+
+func (s *snoutSuite) TestMalformedDotEnvFile() {
+	s.Run("Given a dotenv file with an unterminated quote", func() {
+		type stubConfig struct {
+			A string `snout:"a"`
+		}
+
+		path := s.T().TempDir() + "/.env"
+		s.Require().NoError(os.WriteFile(path, []byte("A=\"unterminated\n"), 0o600))
+
+		s.Run("When the configuration is Checked", func() {
+			kernel := snout.Kernel[stubConfig]{}
+			err := kernel.Bootstrap(context.TODO(), snout.WithDotEnvFiles(path)).Check()
+
+			s.Run("Then a config error naming the file and line is returned", func() {
+				s.Require().ErrorIs(err, snout.ErrConfig)
+				s.Require().ErrorContains(err, path+":1: A: unterminated double quoted value")
+			})
+		})
+	})
+}
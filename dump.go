@@ -0,0 +1,166 @@
+package snout
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+// secretMask replaces the value of secret fields in dumps.
+const secretMask = "******"
+
+// Dump renders the fully merged configuration, after defaults, files, env vars and flags, in the given format using
+// the snout tag keys. Secret fields and fields holding values decrypted from ENC[...] are masked.
+func (kb KernelBootstrap[T]) Dump(format Format) ([]byte, error) {
+	if kb.err != nil {
+		return nil, kb.err
+	}
+
+	root := reflect.ValueOf(&kb.cfg).Elem()
+	n := namingOf(kb.options)
+	doc := document{
+		fields: configFields(root.Type(), n),
+		value: func(f configField) any {
+			if kb.decrypted[f.key()] {
+				return secretMask
+			}
+
+			return dumpValue(f, root, n, kb.options.Variants)
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := doc.write(&buf, format, kb.options.Env); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// printConfig writes the dump of the configuration in the given format, YAML by default, to the kernel output.
+func (kb KernelBootstrap[T]) printConfig(format Format) error {
+	if format == "" {
+		format = FormatYAML
+	}
+
+	out, err := kb.Dump(format)
+	if err != nil {
+		return err
+	}
+
+	_, err = kb.options.Output.Write(out)
+
+	return err
+}
+
+// dumpValue returns the value of a field within root as dumped by dumpField.
+func dumpValue(f configField, root reflect.Value, n naming, variants map[reflect.Type]map[string]reflect.Type) any {
+	v, ok := f.value(root)
+	if !ok {
+		return nil
+	}
+
+	return dumpField(f, v, n, variants)
+}
+
+// dumpField returns v, the value of f, as dumped: secrets masked, durations as text, and the structs within lists,
+// maps and variants as maps keyed by snout keys, so that the dump reads back as configuration.
+func dumpField(f configField, v reflect.Value, n naming, variants map[reflect.Type]map[string]reflect.Type) any {
+	if f.secret() {
+		if v.IsZero() {
+			return ""
+		}
+
+		return secretMask
+	}
+
+	if f.variant() {
+		if v.IsNil() {
+			return nil
+		}
+
+		section := &orderedMap{}
+		section.set(f.discriminator(), variantName(variants[f.typ()], v.Elem().Type()))
+
+		return dumpStruct(section, indirect(v), n, variants)
+	}
+
+	return dumpNested(v, n, variants)
+}
+
+// dumpNested returns v as dumped by dumpField, walking the structs, lists and maps within it.
+func dumpNested(v reflect.Value, n naming, variants map[reflect.Type]map[string]reflect.Type) any {
+	v = indirect(v)
+	if !v.IsValid() {
+		return nil
+	}
+
+	if d, ok := v.Interface().(time.Duration); ok {
+		return d.String()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		if isNestedStruct(v.Type()) {
+			return dumpStruct(&orderedMap{}, v, n, variants)
+		}
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			break
+		}
+
+		items := make([]any, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			items = append(items, dumpNested(v.Index(i), n, variants))
+		}
+
+		return items
+	case reflect.Map:
+		keys := v.MapKeys()
+		slices.SortFunc(keys, func(a, b reflect.Value) int {
+			return strings.Compare(fmt.Sprint(a.Interface()), fmt.Sprint(b.Interface()))
+		})
+
+		entries := &orderedMap{}
+		for _, key := range keys {
+			entries.set(fmt.Sprint(key.Interface()), dumpNested(v.MapIndex(key), n, variants))
+		}
+
+		return entries
+	}
+
+	return v.Interface()
+}
+
+// dumpStruct sets the dump of every field of v, a struct holding configuration keys, in m.
+func dumpStruct(m *orderedMap, v reflect.Value, n naming, variants map[reflect.Type]map[string]reflect.Type,
+) *orderedMap {
+	for _, f := range configFields(v.Type(), n) {
+		mapping := m
+		segments := strings.Split(f.key(), ".")
+
+		for _, segment := range segments[:len(segments)-1] {
+			mapping = mapping.child(segment)
+		}
+
+		mapping.set(segments[len(segments)-1], dumpValue(f, v, n, variants))
+	}
+
+	return m
+}
+
+// indirect removes any pointer and interface indirection from v, returning the zero Value when it is nil.
+func indirect(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+
+		v = v.Elem()
+	}
+
+	return v
+}
@@ -0,0 +1,308 @@
+package snout_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"time"
+
+	"github.com/chiguirez/snout/v3"
+)
+
+type dumpConfig struct {
+	A string `snout:"a"`
+	B int    `snout:"b"`
+	D *struct {
+		A *string  `snout:"a"`
+		B *float64 `snout:"b"`
+		C *bool    `snout:"c"`
+	} `snout:"d"`
+	E        time.Duration `snout:"e"`
+	Password string        `snout:"password" default:"hunter2" secret:"true"`
+}
+
+func (s *snoutSuite) TestDumpYAML() {
+	s.Run("Given a config Struct loaded from a JSON file and defaults", func() {
+		kernel := snout.Kernel[dumpConfig]{}
+
+		kb := kernel.Bootstrap(
+			context.TODO(),
+			snout.WithServiceName("JSON"),
+			snout.WithEnvVarFolderLocation("./testdata/"),
+		)
+
+		s.Run("When the configuration is dumped as YAML", func() {
+			out, err := kb.Dump(snout.FormatYAML)
+			s.Require().NoError(err)
+
+			s.Run("Then the merged values are rendered and secrets masked", func() {
+				s.Require().Equal(`a: a
+b: 1
+d:
+  a: da
+  b: 3.1415
+  c: false
+e: 30m0s
+password: '******'
+`, string(out))
+			})
+		})
+	})
+}
+
+func (s *snoutSuite) TestDumpEncrypted() {
+	s.Run("Given an encrypted value on a field not tagged secret", func() {
+		type stubConfig struct {
+			User  string `snout:"user"`
+			Token string `snout:"token"`
+		}
+
+		kernel := snout.Kernel[stubConfig]{}
+
+		kb := kernel.Bootstrap(
+			context.TODO(),
+			snout.WithArgs(),
+			snout.WithConfigReader(strings.NewReader("user: app\ntoken: ENC[nekot]\n"), snout.FormatYAML),
+			snout.WithDecryptor(reverseDecryptor{}),
+		)
+
+		s.Run("When the configuration is dumped", func() {
+			out, err := kb.Dump(snout.FormatEnv)
+			s.Require().NoError(err)
+
+			s.Run("Then the decrypted value is masked", func() {
+				s.Require().Contains(string(out), "USER=app")
+				s.Require().Contains(string(out), "TOKEN=******")
+				s.Require().NotContains(string(out), "=token")
+			})
+		})
+	})
+}
+
+func (s *snoutSuite) TestDumpEnvLoadsBack() {
+	s.Run("Given a config Struct with lists of text and numbers", func() {
+		type stubConfig struct {
+			Tags    []string `snout:"tags"`
+			Retries []int    `snout:"retries"`
+			Name    string   `snout:"name"`
+		}
+
+		cfgChan := make(chan stubConfig, 1)
+
+		kernel := snout.Kernel[stubConfig]{RunE: func(_ context.Context, config stubConfig) error {
+			cfgChan <- config
+
+			return nil
+		}}
+
+		kb := kernel.Bootstrap(context.TODO(), snout.WithArgs(), snout.WithEnvVarPrefix("APP"),
+			snout.WithConfigReader(strings.NewReader("tags: [a, b]\nretries: [1, 2]\nname: orders\n"), snout.FormatYAML))
+
+		s.Run("When its env dump is loaded back as env vars", func() {
+			out, err := kb.Dump(snout.FormatEnv)
+			s.Require().NoError(err)
+
+			vars := map[string]string{}
+			for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+				name, value, _ := strings.Cut(line, "=")
+				vars[name] = value
+			}
+
+			err = kernel.Bootstrap(context.TODO(), snout.WithArgs(), snout.WithEnvVarPrefix("APP"),
+				snout.WithLookupEnv(func(name string) (string, bool) {
+					value, ok := vars[name]
+
+					return value, ok
+				}),
+			).Initialize()
+			s.Require().NoError(err)
+
+			s.Run("Then it loads the same configuration", func() {
+				s.Require().Equal(`["a","b"]`, vars["APP_TAGS"])
+				s.Require().Equal(stubConfig{Tags: []string{"a", "b"}, Retries: []int{1, 2}, Name: "orders"}, <-cfgChan)
+			})
+		})
+	})
+}
+
+func (s *snoutSuite) TestDumpJSON() {
+	s.Run("Given a config Struct loaded from a JSON file and defaults", func() {
+		kernel := snout.Kernel[dumpConfig]{}
+
+		kb := kernel.Bootstrap(
+			context.TODO(),
+			snout.WithServiceName("JSON"),
+			snout.WithEnvVarFolderLocation("./testdata/"),
+		)
+
+		s.Run("When the configuration is dumped as JSON", func() {
+			out, err := kb.Dump(snout.FormatJSON)
+			s.Require().NoError(err)
+
+			s.Run("Then the merged values are rendered and secrets masked", func() {
+				s.Require().JSONEq(`{
+  "a": "a",
+  "b": 1,
+  "d": {"a": "da", "b": 3.1415, "c": false},
+  "e": "30m0s",
+  "password": "******"
+}`, string(out))
+			})
+		})
+	})
+}
+
+func (s *snoutSuite) TestPrintConfigFlag() {
+	s.Run("Given a kernel started with --print-config=env", func() {
+		ran := false
+
+		kernel := snout.Kernel[dumpConfig]{RunE: func(context.Context, dumpConfig) error {
+			ran = true
+
+			return nil
+		}}
+
+		s.Run("When Kernel is Initialized", func() {
+			var out bytes.Buffer
+
+			err := kernel.Bootstrap(
+				context.TODO(),
+				snout.WithServiceName("YAML"),
+				snout.WithEnvVarFolderLocation("./testdata/"),
+				snout.WithEnvVarPrefix("SVC"),
+				snout.WithArgs("--print-config=env"),
+				snout.WithOutput(&out),
+			).Initialize()
+
+			s.Run("Then the resolved env vars are printed and the kernel is not run", func() {
+				s.Require().NoError(err)
+				s.Require().False(ran)
+				s.Require().Equal(`SVC_A=a
+SVC_B=1
+SVC_D_A=da
+SVC_D_B=3.1415
+SVC_D_C=false
+SVC_E=30m0s
+SVC_PASSWORD=******
+`, out.String())
+			})
+		})
+	})
+}
+
+type dumpCredentials struct {
+	User     string `snout:"user"`
+	Password string `snout:"password" secret:"true"`
+}
+
+type dumpUpstream struct {
+	Host        string          `snout:"host"`
+	Credentials dumpCredentials `snout:"credentials"`
+}
+
+type dumpQueue struct {
+	URL   string `snout:"url"`
+	Token string `snout:"token" secret:"true"`
+}
+
+func (*dumpQueue) Kind() string { return "sqs" }
+
+type dumpCollectionConfig struct {
+	Upstreams []dumpUpstream          `snout:"upstreams"`
+	Tenants   map[string]dumpUpstream `snout:"tenants"`
+	Queue     queue                   `snout:"queue"`
+}
+
+func (s *snoutSuite) TestDumpCollections() {
+	s.Run("Given a config Struct with secrets inside lists, maps and variants", func() {
+		kernel := snout.Kernel[dumpCollectionConfig]{}
+
+		kb := kernel.Bootstrap(
+			context.TODO(),
+			snout.WithEnvVarPrefix("APP"),
+			snout.WithVariant[queue]("sqs", &dumpQueue{}),
+			snout.WithConfigReader(strings.NewReader(`
+upstreams:
+  - host: a
+    credentials: {user: admin, password: hunter2}
+tenants:
+  acme:
+    host: b
+    credentials: {password: hunter3}
+queue: {type: sqs, url: https://sqs/q, token: hunter4}
+`), snout.FormatYAML),
+			snout.WithArgs(),
+		)
+
+		s.Run("When the configuration is dumped as YAML", func() {
+			out, err := kb.Dump(snout.FormatYAML)
+			s.Require().NoError(err)
+
+			s.Run("Then elements are keyed by snout keys, variants name their type and secrets are masked", func() {
+				s.Require().Equal(`upstreams:
+  - host: a
+    credentials:
+      user: admin
+      password: '******'
+tenants:
+  acme:
+    host: b
+    credentials:
+      user: ""
+      password: '******'
+queue:
+  type: sqs
+  url: https://sqs/q
+  token: '******'
+`, string(out))
+			})
+		})
+
+		s.Run("When the configuration is dumped as env vars", func() {
+			out, err := kb.Dump(snout.FormatEnv)
+			s.Require().NoError(err)
+
+			s.Run("Then collections are written as JSON with secrets masked", func() {
+				s.Require().Equal(`APP_UPSTREAMS=[{"host":"a","credentials":{"user":"admin","password":"******"}}]
+APP_TENANTS={"acme":{"host":"b","credentials":{"user":"","password":"******"}}}
+APP_QUEUE={"type":"sqs","url":"https://sqs/q","token":"******"}
+`, string(out))
+			})
+		})
+
+		s.Run("When an env dump is read back", func() {
+			out, err := kb.Dump(snout.FormatEnv)
+			s.Require().NoError(err)
+
+			env := map[string]string{}
+			for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+				name, value, _ := strings.Cut(line, "=")
+				env[name] = value
+			}
+
+			cfgChan := make(chan dumpCollectionConfig, 1)
+			reloaded := snout.Kernel[dumpCollectionConfig]{RunE: func(_ context.Context, config dumpCollectionConfig) error {
+				cfgChan <- config
+
+				return nil
+			}}
+
+			err = reloaded.Bootstrap(context.TODO(), snout.WithEnvVarPrefix("APP"),
+				snout.WithVariant[queue]("sqs", &dumpQueue{}), snout.WithArgs(),
+				snout.WithLookupEnv(func(name string) (string, bool) {
+					value, ok := env[name]
+
+					return value, ok
+				})).Initialize()
+
+			s.Run("Then it loads the same configuration, but for the secrets", func() {
+				s.Require().NoError(err)
+
+				cfg := <-cfgChan
+				s.Require().Equal("admin", cfg.Upstreams[0].Credentials.User)
+				s.Require().Equal("b", cfg.Tenants["acme"].Host)
+				s.Require().Equal(&dumpQueue{URL: "https://sqs/q", Token: "******"}, cfg.Queue)
+			})
+		})
+	})
+}
@@ -0,0 +1,139 @@
+package snout_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"time"
+
+	"github.com/chiguirez/snout/v3"
+)
+
+// CommonHTTP is shared by several service configs, flattened into their keys.
+type CommonHTTP struct {
+	Port    int           `snout:"port" default:"8080" validate:"gte=1024"`
+	Timeout time.Duration `snout:"timeout" default:"5s"`
+}
+
+// CommonDB is shared by several service configs, nested under the key named by its tag.
+type CommonDB struct {
+	URL string `snout:"url" validate:"required"`
+}
+
+type embeddedConfig struct {
+	CommonHTTP
+	*CommonDB `snout:"db"`
+	Name      string `snout:"name"`
+}
+
+type flattenedConfig struct {
+	CommonHTTP
+	Name string `snout:"name"`
+}
+
+func (s *snoutSuite) TestEmbeddedStructs() {
+	bootstrap := func(opts ...snout.Options) (embeddedConfig, error) {
+		cfgChan := make(chan embeddedConfig, 1)
+
+		kernel := snout.Kernel[embeddedConfig]{RunE: func(_ context.Context, config embeddedConfig) error {
+			cfgChan <- config
+
+			return nil
+		}}
+
+		opts = append([]snout.Options{snout.WithEnvVarPrefix("APP")}, opts...)
+		if err := kernel.Bootstrap(context.TODO(), opts...).Initialize(); err != nil {
+			return embeddedConfig{}, err
+		}
+
+		return <-cfgChan, nil
+	}
+
+	env := func(vars map[string]string) snout.Options {
+		return snout.WithLookupEnv(func(name string) (string, bool) {
+			value, ok := vars[name]
+
+			return value, ok
+		})
+	}
+
+	s.Run("Given a config Struct embedding a struct without tag and another one tagged db", func() {
+		s.Run("When it is loaded from a config file, env vars and flags", func() {
+			cfg, err := bootstrap(
+				snout.WithConfigReader(strings.NewReader("port: 9090\nname: orders\n"), snout.FormatYAML),
+				env(map[string]string{"APP_TIMEOUT": "30s"}),
+				snout.WithArgs("--db.url=postgres://db"),
+			)
+
+			s.Run("Then the untagged struct reads the keys of its parent and the tagged one its own", func() {
+				s.Require().NoError(err)
+				s.Require().Equal(9090, cfg.Port)
+				s.Require().Equal(30*time.Second, cfg.Timeout)
+				s.Require().Equal("orders", cfg.Name)
+				s.Require().NotNil(cfg.CommonDB)
+				s.Require().Equal("postgres://db", cfg.URL)
+			})
+		})
+
+		s.Run("When it is loaded without values for the embedded structs", func() {
+			cfg, err := bootstrap(env(map[string]string{"APP_DB_URL": "postgres://db"}), snout.WithArgs())
+
+			s.Run("Then the flattened fields take their defaults", func() {
+				s.Require().NoError(err)
+				s.Require().Equal(8080, cfg.Port)
+				s.Require().Equal(5*time.Second, cfg.Timeout)
+				s.Require().Equal("postgres://db", cfg.URL)
+			})
+		})
+
+		s.Run("When it breaks the validate tags of the embedded structs", func() {
+			_, err := bootstrap(snout.WithArgs("--port=80"))
+
+			s.Run("Then the failing fields are reported by their keys", func() {
+				var errs snout.ValidationErrors
+				s.Require().ErrorAs(err, &errs)
+				s.Require().Equal(snout.ValidationErrors{
+					{Key: "port", Rule: "gte", Param: "1024"},
+					{Key: "db.url", Rule: "required"},
+				}, errs)
+			})
+		})
+
+		s.Run("When its reference is written", func() {
+			var buf bytes.Buffer
+
+			err := new(snout.Kernel[embeddedConfig]).WriteReference(&buf, snout.FormatSchema, snout.WithEnvVarPrefix("APP"))
+
+			s.Run("Then it lists the flattened keys alongside those of the parent", func() {
+				s.Require().NoError(err)
+				s.Require().Equal(`KEY      ENV          FLAG       TYPE           DEFAULT
+port     APP_PORT     --port     int            "8080"
+timeout  APP_TIMEOUT  --timeout  time.Duration  "5s"
+db.url   APP_DB_URL   --db.url   string         ""
+name     APP_NAME     --name     string         ""
+`, buf.String())
+			})
+		})
+	})
+	s.Run("Given a config Struct only embedding a struct without tag", func() {
+		cfgChan := make(chan flattenedConfig, 1)
+
+		kernel := snout.Kernel[flattenedConfig]{RunE: func(_ context.Context, config flattenedConfig) error {
+			cfgChan <- config
+
+			return nil
+		}}
+
+		s.Run("When it is loaded from flags", func() {
+			err := kernel.Bootstrap(context.TODO(), snout.WithArgs("--port=9090", "--timeout=1m")).Initialize()
+
+			s.Run("Then the flags are named after the keys of the parent", func() {
+				s.Require().NoError(err)
+
+				cfg := <-cfgChan
+				s.Require().Equal(9090, cfg.Port)
+				s.Require().Equal(time.Minute, cfg.Timeout)
+			})
+		})
+	})
+}
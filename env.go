@@ -0,0 +1,37 @@
+package snout
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ErrEnvCollision is an error indicating two keys of the configuration are bound to the same env var, such as
+// a.b_c and a_b.c both mapping to A_B_C.
+var ErrEnvCollision = errors.New("env var collision")
+
+// checkEnvNames returns an ErrEnvCollision naming both keys when two of fields are bound to the same env var. A field
+// and its own aliases never collide, as they are the same key under different names.
+func checkEnvNames(fields []configField, env Env) error {
+	owners := make(map[string]configField, len(fields))
+
+	for _, f := range fields {
+		name := f.envName(env)
+		if name == "" {
+			continue
+		}
+
+		owner, ok := owners[name]
+		if !ok {
+			owners[name] = f
+
+			continue
+		}
+
+		if owner.path != f.path && !slices.Equal(owner.index, f.index) {
+			return fmt.Errorf("%w: %s is bound to both %s and %s", ErrEnvCollision, name, owner.key(), f.key())
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package snout_test
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/chiguirez/snout/v3"
+)
+
+type envCollisionConfig struct {
+	A struct {
+		BC string `snout:"b_c"`
+	} `snout:"a"`
+	AB struct {
+		C string `snout:"c"`
+	} `snout:"a_b"`
+}
+
+type envSeparatorConfig struct {
+	Kafka struct {
+		BrokerAddress string `snout:"broker_address"`
+	} `snout:"kafka"`
+	KafkaBroker struct {
+		Address string `snout:"address"`
+	} `snout:"kafka_broker"`
+}
+
+func (s *snoutSuite) TestEnvCollision() {
+	env := snout.WithLookupEnv(func(name string) (string, bool) {
+		value, ok := map[string]string{"APP_A_B_C": "abc", "APP_A__B_C": "a.b_c", "APP_A_B__C": "a_b.c"}[name]
+
+		return value, ok
+	})
+
+	s.Run("Given a config Struct with two keys bound to the same env var", func() {
+		kernel := snout.Kernel[envCollisionConfig]{RunE: func(context.Context, envCollisionConfig) error { return nil }}
+
+		s.Run("When the Kernel is bootstrapped", func() {
+			err := kernel.Bootstrap(context.TODO(), snout.WithArgs(), snout.WithEnvVarPrefix("APP"), env).Initialize()
+
+			s.Run("Then it fails naming both keys", func() {
+				s.Require().ErrorIs(err, snout.ErrEnvCollision)
+				s.Require().ErrorContains(err, "APP_A_B_C is bound to both a.b_c and a_b.c")
+			})
+		})
+
+		s.Run("When the Kernel is bootstrapped with a double underscore key separator", func() {
+			cfgChan := make(chan envCollisionConfig, 1)
+			kernel.RunE = func(_ context.Context, cfg envCollisionConfig) error {
+				cfgChan <- cfg
+
+				return nil
+			}
+
+			err := kernel.Bootstrap(context.TODO(), snout.WithArgs(), snout.WithEnvVarPrefix("APP"),
+				snout.WithEnvKeySeparator("__"), env).Initialize()
+
+			s.Run("Then each key reads its own env var", func() {
+				s.Require().NoError(err)
+
+				cfg := <-cfgChan
+				s.Require().Equal("a.b_c", cfg.A.BC)
+				s.Require().Equal("a_b.c", cfg.AB.C)
+			})
+		})
+	})
+}
+
+func (s *snoutSuite) TestEnvKeySeparator() {
+	s.Run("Given a config Struct with nested keys holding underscores", func() {
+		var kernel snout.Kernel[envSeparatorConfig]
+
+		s.Run("When its env template is written with a double underscore key separator", func() {
+			var buf bytes.Buffer
+
+			err := kernel.WriteTemplate(&buf, snout.FormatEnv, snout.WithEnvVarPrefix("APP"),
+				snout.WithEnvKeySeparator("__"))
+
+			s.Run("Then env vars are named with the separator between nesting levels", func() {
+				s.Require().NoError(err)
+				s.Require().Contains(buf.String(), "APP_KAFKA__BROKER_ADDRESS=")
+				s.Require().Contains(buf.String(), "APP_KAFKA_BROKER__ADDRESS=")
+			})
+		})
+	})
+}
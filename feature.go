@@ -0,0 +1,253 @@
+package snout
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Feature is a feature flag, declared as a field of the configuration struct like any other key:
+//
+//	type Config struct {
+//		NewCheckout snout.Feature `snout:"new_checkout" default:"false"`
+//		FastSearch  snout.Feature `snout:"fast_search" default:"25%"`
+//	}
+//
+// Its value is either a boolean, turning the feature on or off for everyone, or a rollout percentage such as 25% or
+// 12.5, turning it on for that share of the rollout keys set with ContextWithRolloutKey. It is read from config
+// files, sources, env vars and flags as any other key, and a kernel with features keeps them up to date while RunE
+// is running: every copy of the configuration handed out by the kernel sees the values reloaded when the config file
+// or a watched source changes.
+type Feature struct {
+	state *featureState
+}
+
+// featureState is the value of a feature shared by every copy of the configuration it was loaded into.
+type featureState struct {
+	mu         sync.RWMutex
+	key        string
+	percentage float64
+}
+
+// Enabled reports whether the feature is on for the rollout key of ctx. A feature rolled out to a percentage is on
+// for the same keys every time, and off when ctx carries no rollout key.
+func (f Feature) Enabled(ctx context.Context) bool {
+	percentage := f.Percentage()
+
+	switch {
+	case percentage >= 100:
+		return true
+	case percentage <= 0:
+		return false
+	}
+
+	key, ok := ctx.Value(rolloutKey{}).(string)
+	if !ok {
+		return false
+	}
+
+	return rolloutBucket(f.state.name(), key) < percentage
+}
+
+// Percentage returns the share of rollout keys the feature is on for, 100 when on for everyone and 0 when off.
+func (f Feature) Percentage() float64 {
+	if f.state == nil {
+		return 0
+	}
+
+	f.state.mu.RLock()
+	defer f.state.mu.RUnlock()
+
+	return f.state.percentage
+}
+
+// String returns the value of the feature as it is written in config, true, false or a percentage such as 25%.
+func (f Feature) String() string {
+	switch percentage := f.Percentage(); percentage {
+	case 100:
+		return "true"
+	case 0:
+		return "false"
+	default:
+		return strconv.FormatFloat(percentage, 'f', -1, 64) + "%"
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler, so that features print as their config value.
+func (f Feature) MarshalText() ([]byte, error) {
+	return []byte(f.String()), nil
+}
+
+// Set implements the flag value interface, so that features are set with --new_checkout or --fast_search=25%.
+func (f *Feature) Set(value string) error {
+	percentage, err := parseFeature(value)
+	if err != nil {
+		return err
+	}
+
+	f.store(percentage)
+
+	return nil
+}
+
+// Type implements the flag value interface.
+func (f *Feature) Type() string {
+	return "feature"
+}
+
+// IsBoolFlag reports that the feature flag may be given without value to turn it on.
+func (f *Feature) IsBoolFlag() bool {
+	return true
+}
+
+// store sets the percentage of the feature, creating its state when missing.
+func (f *Feature) store(percentage float64) {
+	if f.state == nil {
+		f.state = &featureState{}
+	}
+
+	f.state.mu.Lock()
+	defer f.state.mu.Unlock()
+
+	f.state.percentage = percentage
+}
+
+// name returns the key the feature was loaded from, which spreads rollout keys differently for every feature.
+func (s *featureState) name() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.key
+}
+
+// rolloutKey is the context key under which ContextWithRolloutKey stores the rollout key.
+type rolloutKey struct{}
+
+// ContextWithRolloutKey returns a copy of ctx carrying key, such as a user or tenant id, for percentage rollouts to
+// evaluate features against.
+func ContextWithRolloutKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, rolloutKey{}, key)
+}
+
+// rolloutBucket places key within [0, 100) for the feature name, with a resolution of a hundredth of a percent.
+func rolloutBucket(name, key string) float64 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(name + ":" + key))
+
+	return float64(h.Sum32()%10000) / 100
+}
+
+// parseFeature parses the value of a feature: a boolean, or a percentage between 0 and 100 with or without % sign.
+func parseFeature(value string) (float64, error) {
+	value = strings.TrimSpace(value)
+
+	if on, err := strconv.ParseBool(value); err == nil {
+		if on {
+			return 100, nil
+		}
+
+		return 0, nil
+	}
+
+	percentage, err := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+	if err != nil || percentage < 0 || percentage > 100 {
+		return 0, fmt.Errorf("invalid feature value %q: expecting true, false or a percentage between 0 and 100", value)
+	}
+
+	return percentage, nil
+}
+
+// featureHookFunc decodes config values into features: booleans, numbers taken as percentages and strings parsed
+// with parseFeature.
+func featureHookFunc(f reflect.Type, t reflect.Type, data interface{}) (interface{}, error) {
+	if t != reflect.TypeOf(Feature{}) {
+		return data, nil
+	}
+
+	var feature Feature
+
+	switch value := data.(type) {
+	case Feature:
+		return value, nil
+	case bool:
+		if value {
+			feature.store(100)
+		} else {
+			feature.store(0)
+		}
+	default:
+		if err := feature.Set(fmt.Sprint(value)); err != nil {
+			return nil, err
+		}
+	}
+
+	return feature, nil
+}
+
+// hasFeatures reports whether any of fields is a feature.
+func hasFeatures(fields []configField) bool {
+	for _, f := range fields {
+		if f.typ() == reflect.TypeOf(Feature{}) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// bindFeatures names the features of cfg, a pointer to a configuration struct, after their keys, giving features
+// left unset a state of their own so that reloads can turn them on.
+func bindFeatures(cfg any, fields []configField) {
+	root := reflect.ValueOf(cfg).Elem()
+
+	for _, f := range fields {
+		v, ok := f.value(root)
+		if !ok || v.Type() != reflect.TypeOf(Feature{}) {
+			continue
+		}
+
+		feature := v.Addr().Interface().(*Feature)
+		if feature.state == nil {
+			feature.store(0)
+		}
+
+		feature.state.mu.Lock()
+		feature.state.key = f.key()
+		feature.state.mu.Unlock()
+	}
+}
+
+// updateFeatures stores the values of the features of next, a pointer to a reloaded configuration, into the features
+// of current, the configuration handed to RunE, so that every copy of it sees them. The features of next are then
+// pointed at those of current, so that the reloaded configuration keeps seeing the values of later reloads too.
+func updateFeatures(current, next any, fields []configField) {
+	currentRoot, nextRoot := reflect.ValueOf(current), reflect.ValueOf(next)
+
+	for _, f := range fields {
+		if f.typ() != reflect.TypeOf(Feature{}) {
+			continue
+		}
+
+		currentValue, ok := f.value(currentRoot)
+		if !ok {
+			continue
+		}
+
+		nextValue, ok := f.value(nextRoot)
+		if !ok {
+			continue
+		}
+
+		feature := currentValue.Interface().(Feature)
+		if feature.state == nil {
+			continue
+		}
+
+		feature.store(nextValue.Interface().(Feature).Percentage())
+		nextValue.Set(currentValue)
+	}
+}
@@ -0,0 +1,241 @@
+package snout_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/chiguirez/snout/v3"
+)
+
+type featureConfig struct {
+	Checkout snout.Feature `snout:"checkout"`
+	Search   snout.Feature `snout:"search" default:"25%"`
+	Beta     struct {
+		Dashboard snout.Feature `snout:"dashboard" default:"true"`
+	} `snout:"beta"`
+}
+
+func (s *snoutSuite) TestFeature() {
+	s.Run("Given a config Struct with features", func() {
+		cfgChan := make(chan featureConfig, 1)
+
+		kernel := snout.Kernel[featureConfig]{RunE: func(_ context.Context, config featureConfig) error {
+			cfgChan <- config
+
+			return nil
+		}}
+
+		s.Run("When Kernel is Initialized with a file, env vars and flags setting them", func() {
+			err := kernel.Bootstrap(
+				context.TODO(),
+				snout.WithConfigReader(strings.NewReader("checkout: true\nsearch: 50\n"), snout.FormatYAML),
+				snout.WithLookupEnv(func(name string) (string, bool) {
+					if name == "SEARCH" {
+						return "12.5%", true
+					}
+
+					return "", false
+				}),
+				snout.WithArgs("--beta.dashboard=false"),
+			).Initialize()
+			s.Require().NoError(err)
+
+			s.Run("Then env vars and flags override the file", func() {
+				config := <-cfgChan
+				s.Require().True(config.Checkout.Enabled(context.TODO()))
+				s.Require().Equal(12.5, config.Search.Percentage())
+				s.Require().False(config.Beta.Dashboard.Enabled(context.TODO()))
+				s.Require().Equal("12.5%", config.Search.String())
+			})
+		})
+
+		s.Run("When Kernel is Initialized without values", func() {
+			err := kernel.Bootstrap(context.TODO(), snout.WithArgs(), snout.WithEnvVarFolderLocation(s.T().TempDir())).
+				Initialize()
+			s.Require().NoError(err)
+
+			s.Run("Then defaults apply and features without default are off", func() {
+				config := <-cfgChan
+				s.Require().False(config.Checkout.Enabled(context.TODO()))
+				s.Require().Equal(25.0, config.Search.Percentage())
+				s.Require().True(config.Beta.Dashboard.Enabled(context.TODO()))
+			})
+		})
+	})
+}
+
+func (s *snoutSuite) TestFeatureRollout() {
+	s.Run("Given a feature rolled out to 30% of the keys", func() {
+		cfgChan := make(chan featureConfig, 1)
+
+		kernel := snout.Kernel[featureConfig]{RunE: func(_ context.Context, config featureConfig) error {
+			cfgChan <- config
+
+			return nil
+		}}
+
+		err := kernel.Bootstrap(context.TODO(), snout.WithArgs("--search=30%")).Initialize()
+		s.Require().NoError(err)
+
+		search := (<-cfgChan).Search
+
+		s.Run("When it is evaluated for many rollout keys", func() {
+			enabled := 0
+
+			for i := 0; i < 2000; i++ {
+				ctx := snout.ContextWithRolloutKey(context.TODO(), fmt.Sprintf("user-%d", i))
+				if search.Enabled(ctx) {
+					enabled++
+				}
+
+				s.Require().Equal(search.Enabled(ctx), search.Enabled(ctx))
+			}
+
+			s.Run("Then it is on for about that share of them, and off without rollout key", func() {
+				s.Require().InDelta(600, enabled, 100)
+				s.Require().False(search.Enabled(context.TODO()))
+			})
+		})
+	})
+}
+
+func (s *snoutSuite) TestFeatureInvalid() {
+	s.Run("Given a feature set to something else than a boolean or percentage", func() {
+		kernel := snout.Kernel[featureConfig]{}
+
+		s.Run("When the configuration is Checked", func() {
+			err := kernel.Bootstrap(context.TODO(),
+				snout.WithConfigReader(strings.NewReader("search: 150%"), snout.FormatYAML),
+			).Check()
+
+			s.Run("Then a config error is returned", func() {
+				s.Require().ErrorIs(err, snout.ErrConfig)
+				s.Require().ErrorContains(err, `invalid feature value "150%"`)
+			})
+		})
+	})
+}
+
+func (s *snoutSuite) TestFeatureReload() {
+	s.Run("Given a Kernel running with features read from a config file", func() {
+		dir := s.T().TempDir()
+		path := filepath.Join(dir, "config.yaml")
+
+		s.Require().NoError(os.WriteFile(path, []byte("checkout: false\n"), 0o600))
+
+		started := make(chan featureConfig, 1)
+
+		kernel := snout.Kernel[featureConfig]{RunE: func(ctx context.Context, config featureConfig) error {
+			started <- config
+			<-ctx.Done()
+
+			return nil
+		}}
+
+		ctx, cancel := context.WithCancel(context.TODO())
+		defer cancel()
+
+		done := make(chan error, 1)
+
+		go func() {
+			done <- kernel.Bootstrap(ctx, snout.WithArgs(), snout.WithEnvVarFolderLocation(dir)).Initialize()
+		}()
+
+		config := <-started
+		s.Require().False(config.Checkout.Enabled(context.TODO()))
+
+		s.Run("When the config file changes", func() {
+			writes := 0
+
+			s.Run("Then the configuration handed to RunE sees the new value", func() {
+				s.Require().Eventually(func() bool {
+					// the file is written again until the change is seen, as the watcher may start after a first write
+					writes++
+					s.Require().NoError(os.WriteFile(path, []byte(fmt.Sprintf("checkout: true # %d\n", writes)), 0o600))
+
+					return config.Checkout.Enabled(context.TODO())
+				}, 5*time.Second, 50*time.Millisecond)
+			})
+		})
+
+		cancel()
+		s.Require().NoError(<-done)
+	})
+}
+
+func (s *snoutSuite) TestFeatureReloadKeepsOnReloadConfig() {
+	s.Run("Given a Kernel running with features and an OnReload function", func() {
+		dir := s.T().TempDir()
+		path := filepath.Join(dir, "config.yaml")
+
+		s.Require().NoError(os.WriteFile(path, []byte("checkout: false\n"), 0o600))
+
+		started := make(chan struct{})
+		reloaded := make(chan featureConfig, 1)
+
+		kernel := snout.Kernel[featureConfig]{
+			RunE: func(ctx context.Context, _ featureConfig) error {
+				close(started)
+				<-ctx.Done()
+
+				return nil
+			},
+			OnReload: func(_ context.Context, config featureConfig) error {
+				select {
+				case reloaded <- config:
+				default:
+				}
+
+				return nil
+			},
+		}
+
+		ctx, cancel := context.WithCancel(context.TODO())
+		defer cancel()
+
+		done := make(chan error, 1)
+
+		go func() {
+			done <- kernel.Bootstrap(ctx, snout.WithArgs(), snout.WithEnvVarFolderLocation(dir)).Initialize()
+		}()
+
+		<-started
+
+		s.Run("When the config file turns the feature on and then off again", func() {
+			var config featureConfig
+
+			writes := 0
+
+			s.Require().Eventually(func() bool {
+				// the file is written again until a reload is seen, as the watcher may start after a first write
+				writes++
+				s.Require().NoError(os.WriteFile(path, []byte(fmt.Sprintf("checkout: true # %d\n", writes)), 0o600))
+
+				select {
+				case config = <-reloaded:
+					return true
+				case <-time.After(50 * time.Millisecond):
+					return false
+				}
+			}, 5*time.Second, time.Millisecond)
+
+			s.Require().True(config.Checkout.Enabled(context.TODO()))
+
+			s.Run("Then the configuration handed to the first OnReload sees the second reload", func() {
+				s.Require().Eventually(func() bool {
+					writes++
+					s.Require().NoError(os.WriteFile(path, []byte(fmt.Sprintf("checkout: false # %d\n", writes)), 0o600))
+
+					return !config.Checkout.Enabled(context.TODO())
+				}, 5*time.Second, 50*time.Millisecond)
+			})
+		})
+
+		cancel()
+		s.Require().NoError(<-done)
+	})
+}
@@ -0,0 +1,271 @@
+package snout
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// configField is a leaf of a configuration struct as seen by snout: the dotted key path built from the snout tags
+// and the struct field that key decodes into, along with its index sequence from the root struct.
+type configField struct {
+	path  string
+	field reflect.StructField
+	index []int
+}
+
+// key returns the dotted configuration key of the field, e.g. kafka.broker_address.
+func (f configField) key() string {
+	return f.path
+}
+
+// envName returns the environment variable bound to the field for the prefix and key separator of env, e.g.
+// APP_KAFKA_BROKER_ADDRESS, or APP_KAFKA__BROKER_ADDRESS with a double underscore separator. An env tag names the
+// variable as is, without prefix, as in `env:"PORT"`, and `env:"-"` leaves the field unbound, returning "".
+func (f configField) envName(env Env) string {
+	switch name := f.field.Tag.Get("env"); name {
+	case "-":
+		return ""
+	case "":
+	default:
+		return name
+	}
+
+	name := strings.ToUpper(strings.ReplaceAll(f.path, ".", env.keySeparator()))
+	if env.VarsPrefix != "" {
+		return strings.ToUpper(env.VarsPrefix) + "_" + name
+	}
+
+	return name
+}
+
+// flagName returns the command line flag bound to the field, without leading dashes: the key unless named by a flag
+// tag, as in `flag:"port"`. `flag:"-"` leaves the field without flag, returning "".
+func (f configField) flagName() string {
+	switch name := f.field.Tag.Get("flag"); name {
+	case "-":
+		return ""
+	case "":
+		return f.path
+	default:
+		return name
+	}
+}
+
+// shorthand returns the one letter shorthand of the flag of the field from its short tag, as in `short:"p"`.
+func (f configField) shorthand() string {
+	if f.flagName() == "" {
+		return ""
+	}
+
+	return f.field.Tag.Get("short")
+}
+
+// flagUsage returns the flag of the field as written on the command line, e.g. --port, -p, or "" without flag.
+func (f configField) flagUsage() string {
+	switch name, short := f.flagName(), f.shorthand(); {
+	case name == "":
+		return ""
+	case short != "":
+		return "--" + name + ", -" + short
+	default:
+		return "--" + name
+	}
+}
+
+// collection reports whether the field holds a list of structs, a map keyed by strings or a variant of an interface,
+// set by env vars and flags either as a whole, as JSON, or element by element.
+func (f configField) collection() bool {
+	switch t := f.typ(); t.Kind() {
+	case reflect.Slice:
+		return isNestedStruct(t.Elem())
+	case reflect.Map:
+		return t.Key().Kind() == reflect.String
+	case reflect.Interface:
+		return f.variant()
+	default:
+		return false
+	}
+}
+
+// appends reports whether the list of the field is appended to the lists of lower precedence layers rather than
+// replacing them, as set with `merge:"append"`.
+func (f configField) appends() bool {
+	return f.field.Tag.Get("merge") == "append"
+}
+
+// typ returns the type of the field with any pointer indirection removed.
+func (f configField) typ() reflect.Type {
+	return indirectType(f.field.Type)
+}
+
+// defaultValue returns the value of the default tag and whether the field has one.
+func (f configField) defaultValue() (string, bool) {
+	value := f.field.Tag.Get("default")
+
+	return value, value != ""
+}
+
+// required reports whether the validate tag marks the field as required.
+func (f configField) required() bool {
+	for _, rule := range strings.Split(f.validation(), ",") {
+		if rule == "required" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// secret reports whether the field holds a secret, marked with a `secret:"true"` tag.
+func (f configField) secret() bool {
+	return f.field.Tag.Get("secret") == "true"
+}
+
+// description returns the human readable description of the field from its desc tag.
+func (f configField) description() string {
+	return f.field.Tag.Get("desc")
+}
+
+// aliases returns the fields standing for the former keys of the field listed, comma separated, in its alias tag.
+// Aliases are siblings of the key, e.g. kafka.broker_address for `snout:"brokers" alias:"broker_address"` within
+// kafka.
+func (f configField) aliases() []configField {
+	tag := f.field.Tag.Get("alias")
+	if tag == "" {
+		return nil
+	}
+
+	parent := ""
+	if i := strings.LastIndexByte(f.path, '.'); i >= 0 {
+		parent = f.path[:i+1]
+	}
+
+	// Aliases are named after their own keys, only keeping the exclusions of the field from env vars or flags.
+	field := f.field
+	field.Tag = reflect.StructTag(fmt.Sprintf(`env:%q flag:%q`, excluded(f.field.Tag.Get("env")),
+		excluded(f.field.Tag.Get("flag"))))
+
+	names := strings.Split(tag, ",")
+	aliases := make([]configField, 0, len(names))
+
+	for _, name := range names {
+		aliases = append(aliases, configField{path: parent + strings.TrimSpace(name), field: field, index: f.index})
+	}
+
+	return aliases
+}
+
+// excluded returns "-" when name, the value of an env or flag tag, excludes a field, and "" otherwise.
+func excluded(name string) string {
+	if name == "-" {
+		return name
+	}
+
+	return ""
+}
+
+// deprecation returns the deprecation notice of the field from its deprecated tag.
+func (f configField) deprecation() string {
+	return f.field.Tag.Get("deprecated")
+}
+
+// validation returns the validation rules of the field from its validate tag.
+func (f configField) validation() string {
+	return f.field.Tag.Get("validate")
+}
+
+// value returns the value of the field within root, a value of the struct the field was walked from. It reports
+// false when a nil pointer on the way leaves the field unset.
+func (f configField) value(root reflect.Value) (reflect.Value, bool) {
+	v := root
+	for _, i := range f.index {
+		for v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+
+			v = v.Elem()
+		}
+
+		v = v.Field(i)
+	}
+
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return reflect.Value{}, false
+		}
+
+		v = v.Elem()
+	}
+
+	return v, true
+}
+
+// walkFields calls fn for every leaf field of t, descending into nested structs and pointers to structs, with keys
+// named by n. Embedded structs without name in their tag are flattened into the keys of the struct embedding them.
+func walkFields(t reflect.Type, path string, n naming, fn func(configField)) {
+	walkStruct(t, path, nil, n, fn)
+}
+
+// walkStruct walks the fields of t, whose index sequence from the root struct is index.
+func walkStruct(t reflect.Type, path string, index []int, n naming, fn func(configField)) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		fieldIndex := append(append([]int{}, index...), i)
+
+		if n.flattened(field) {
+			walkStruct(indirectType(field.Type), path, fieldIndex, n, fn)
+
+			continue
+		}
+
+		name, ok := n.name(field)
+		if !ok {
+			continue
+		}
+
+		finalPath := name
+		if path != "" {
+			finalPath = path + "." + name
+		}
+
+		if isNestedStruct(field.Type) {
+			walkStruct(indirectType(field.Type), finalPath, fieldIndex, n, fn)
+		} else {
+			fn(configField{path: finalPath, field: field, index: fieldIndex})
+		}
+	}
+}
+
+// configFields returns every leaf field of t in declaration order, with keys named by n.
+func configFields(t reflect.Type, n naming) []configField {
+	var fields []configField
+
+	walkFields(t, "", n, func(f configField) {
+		fields = append(fields, f)
+	})
+
+	return fields
+}
+
+// isNestedStruct reports whether t is a struct, or a pointer to one, holding further configuration keys.
+func isNestedStruct(t reflect.Type) bool {
+	t = indirectType(t)
+
+	return t.Kind() == reflect.Struct && t != reflect.TypeOf(time.Time{}) && t != reflect.TypeOf(Feature{})
+}
+
+// indirectType removes any pointer indirection from t.
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t
+}
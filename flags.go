@@ -0,0 +1,119 @@
+package snout
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"unicode/utf8"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// ErrFlagCollision is an error indicating two keys of the configuration are bound to the same flag or shorthand.
+var ErrFlagCollision = errors.New("flag collision")
+
+// checkFlagNames returns an ErrFlagCollision naming both keys when two of fields are bound to the same flag or
+// shorthand, and an error when a short tag is not a single letter.
+func checkFlagNames(fields []configField) error {
+	names := make(map[string]string, len(fields))
+	shorthands := make(map[string]string, len(fields))
+
+	for _, f := range fields {
+		name := f.flagName()
+		if name == "" {
+			continue
+		}
+
+		if owner, ok := names[name]; ok {
+			return fmt.Errorf("%w: --%s is bound to both %s and %s", ErrFlagCollision, name, owner, f.key())
+		}
+
+		names[name] = f.key()
+
+		short := f.shorthand()
+		if short == "" {
+			continue
+		}
+
+		if utf8.RuneCountInString(short) != 1 {
+			return fmt.Errorf("short tag %q of %s is not a single letter", short, f.key())
+		}
+
+		if owner, ok := shorthands[short]; ok {
+			return fmt.Errorf("%w: -%s is bound to both %s and %s", ErrFlagCollision, short, owner, f.key())
+		}
+
+		shorthands[short] = f.key()
+	}
+
+	return nil
+}
+
+// addFlags adds the flags derived from the configuration struct into flagSet, named after the flag and short tags of
+// their fields and leaving out those excluded with `flag:"-"`, as well as collections, left to defineCollectionFlags.
+func addFlags(flagSet, derived *pflag.FlagSet, fields []configField) {
+	byKey := make(map[string]configField, len(fields))
+	for _, f := range fields {
+		byKey[f.key()] = f
+	}
+
+	derived.VisitAll(func(flag *pflag.Flag) {
+		if f, ok := byKey[flag.Name]; ok {
+			if flag.Name = f.flagName(); flag.Name == "" || f.collection() {
+				return
+			}
+
+			flag.Shorthand = f.shorthand()
+		}
+
+		flagSet.AddFlag(flag)
+	})
+}
+
+// nestsEmbedded reports whether t, at any depth, embeds a struct named by its tag, whose keys gpflag would flatten
+// into those of the struct embedding it.
+func nestsEmbedded(t reflect.Type, n naming) bool {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" || !isNestedStruct(field.Type) {
+			continue
+		}
+
+		if field.Anonymous && !n.flattened(field) || nestsEmbedded(indirectType(field.Type), n) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// bindFlags binds every flag of flagSet to the key of its field in v, or to the key named as the flag for flags
+// without field. The flags of collections are read by collectionFlagMap instead.
+func bindFlags(v *viper.Viper, flagSet *pflag.FlagSet, fields []configField) error {
+	keys := make(map[string]string, len(fields))
+	for _, f := range append(append([]configField{}, fields...), aliasFields(fields)...) {
+		if name := f.flagName(); name != "" {
+			keys[name] = f.key()
+		}
+	}
+
+	var err error
+
+	flagSet.VisitAll(func(flag *pflag.Flag) {
+		if isCollectionFlag(flag.Name, fields) {
+			return
+		}
+
+		key, ok := keys[flag.Name]
+		if !ok {
+			key = flag.Name
+		}
+
+		if bindErr := v.BindPFlag(key, flag); bindErr != nil && err == nil {
+			err = bindErr
+		}
+	})
+
+	return err
+}
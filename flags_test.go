@@ -0,0 +1,107 @@
+package snout_test
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/chiguirez/snout/v3"
+)
+
+type namedConfig struct {
+	Server struct {
+		Port int    `snout:"port" env:"PORT" flag:"port" short:"p" default:"8080"`
+		Host string `snout:"host" default:"localhost"`
+	} `snout:"server"`
+	Database struct {
+		URL string `snout:"url" env:"DATABASE_URL"`
+	} `snout:"database"`
+	Token   string `snout:"token" flag:"-"`
+	Verbose bool   `snout:"verbose" env:"-" short:"v"`
+}
+
+func (s *snoutSuite) TestNameOverrides() {
+	bootstrap := func(env map[string]string, args ...string) (namedConfig, error) {
+		cfgChan := make(chan namedConfig, 1)
+
+		kernel := snout.Kernel[namedConfig]{RunE: func(_ context.Context, config namedConfig) error {
+			cfgChan <- config
+
+			return nil
+		}}
+
+		err := kernel.Bootstrap(context.TODO(), snout.WithArgs(args...), snout.WithEnvVarPrefix("APP"),
+			snout.WithLookupEnv(func(name string) (string, bool) {
+				value, ok := env[name]
+
+				return value, ok
+			})).Initialize()
+		if err != nil {
+			return namedConfig{}, err
+		}
+
+		return <-cfgChan, nil
+	}
+
+	s.Run("Given a config Struct with env, flag and short tags", func() {
+		s.Run("When the Kernel is initialized with the env vars they name", func() {
+			cfg, err := bootstrap(map[string]string{
+				"PORT": "9090", "DATABASE_URL": "postgres://db", "APP_TOKEN": "secret", "APP_VERBOSE": "true",
+			})
+
+			s.Run("Then they are read without prefix and excluded fields ignore theirs", func() {
+				s.Require().NoError(err)
+				s.Require().Equal(9090, cfg.Server.Port)
+				s.Require().Equal("postgres://db", cfg.Database.URL)
+				s.Require().Equal("secret", cfg.Token)
+				s.Require().False(cfg.Verbose)
+			})
+		})
+
+		s.Run("When the Kernel is initialized with the flags they name", func() {
+			cfg, err := bootstrap(nil, "-p", "7070", "-v", "--server.host=example.com", "--token=secret")
+
+			s.Run("Then they are read into their keys and excluded fields ignore theirs", func() {
+				s.Require().NoError(err)
+				s.Require().Equal(7070, cfg.Server.Port)
+				s.Require().Equal("example.com", cfg.Server.Host)
+				s.Require().True(cfg.Verbose)
+				s.Require().Empty(cfg.Token)
+			})
+		})
+
+		s.Run("When its reference is written", func() {
+			var buf bytes.Buffer
+
+			err := new(snout.Kernel[namedConfig]).WriteReference(&buf, snout.FormatSchema, snout.WithEnvVarPrefix("APP"))
+
+			s.Run("Then it lists the names from the tags", func() {
+				s.Require().NoError(err)
+				s.Require().Equal(`KEY           ENV              FLAG            TYPE    DEFAULT
+server.port   PORT             --port, -p      int     "8080"
+server.host   APP_SERVER_HOST  --server.host   string  "localhost"
+database.url  DATABASE_URL     --database.url  string  ""
+token         APP_TOKEN        -               string  ""
+verbose       -                --verbose, -v   bool    ""
+`, buf.String())
+			})
+		})
+	})
+
+	s.Run("Given a config Struct with two keys bound to the same flag", func() {
+		type collidingConfig struct {
+			Port     int `snout:"port" short:"p"`
+			Parallel int `snout:"parallel" short:"p"`
+		}
+
+		kernel := snout.Kernel[collidingConfig]{RunE: func(context.Context, collidingConfig) error { return nil }}
+
+		s.Run("When the Kernel is bootstrapped", func() {
+			err := kernel.Bootstrap(context.TODO(), snout.WithArgs()).Initialize()
+
+			s.Run("Then it fails naming both keys", func() {
+				s.Require().ErrorIs(err, snout.ErrFlagCollision)
+				s.Require().ErrorContains(err, "-p is bound to both port and parallel")
+			})
+		})
+	})
+}
@@ -0,0 +1,182 @@
+package snout
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Group is a constraint between configuration keys, beyond the validate tags of single fields, declared with
+// WithGroups. Groups are checked along validate tags, their failures reported in ValidationErrors by snout key, and
+// listed in the config reference.
+//
+// A key is set when the value decoded into its field is not the zero value. A key naming a nested struct is set when
+// any key within it is.
+type Group struct {
+	rule  string
+	keys  []string
+	when  string
+	value string
+}
+
+// ExactlyOneOf returns a Group requiring exactly one of keys to be set, as in a TLS certificate read either from
+// files or through ACME.
+func ExactlyOneOf(keys ...string) Group {
+	return Group{rule: "exactly_one_of", keys: keys}
+}
+
+// MutuallyExclusive returns a Group allowing at most one of keys to be set.
+func MutuallyExclusive(keys ...string) Group {
+	return Group{rule: "mutually_exclusive", keys: keys}
+}
+
+// RequiredTogether returns a Group requiring keys to be either all set or all unset, as a certificate and its key.
+func RequiredTogether(keys ...string) Group {
+	return Group{rule: "required_together", keys: keys}
+}
+
+// RequiredIf returns a Group requiring keys to be set when the key when holds value, as in auth.client_id being
+// required when auth.mode is oauth.
+func RequiredIf(when, value string, keys ...string) Group {
+	return Group{rule: "required_if", keys: keys, when: when, value: value}
+}
+
+// WithGroups adds groups to the constraints checked when validating the configuration in KernelOptions.
+func WithGroups(groups ...Group) Options {
+	return func(kernel *KernelOptions) {
+		kernel.Groups = append(kernel.Groups, groups...)
+	}
+}
+
+// String describes the group, as listed in the config reference.
+func (g Group) String() string {
+	keys := strings.Join(g.keys, ", ")
+
+	switch g.rule {
+	case "exactly_one_of":
+		return "exactly one of " + keys + " must be set"
+	case "mutually_exclusive":
+		return "at most one of " + keys + " may be set"
+	case "required_together":
+		return keys + " must be set together"
+	case "required_if":
+		return fmt.Sprintf("%s required when %s is %s", keys, g.when, g.value)
+	default:
+		return keys
+	}
+}
+
+// check returns the errors of the keys of the group breaking it, given whether each key is set and the value of the
+// key it depends on.
+func (g Group) check(isSet func(key string) bool, value func(key string) string) ValidationErrors {
+	var set, unset []string
+
+	for _, key := range g.keys {
+		if isSet(key) {
+			set = append(set, key)
+		} else {
+			unset = append(unset, key)
+		}
+	}
+
+	var failing []string
+
+	switch g.rule {
+	case "exactly_one_of":
+		if len(set) == 0 {
+			failing = unset
+		} else if len(set) > 1 {
+			failing = set
+		}
+	case "mutually_exclusive":
+		if len(set) > 1 {
+			failing = set
+		}
+	case "required_together":
+		if len(set) > 0 {
+			failing = unset
+		}
+	case "required_if":
+		if value(g.when) == g.value {
+			failing = unset
+		}
+	}
+
+	errs := make(ValidationErrors, 0, len(failing))
+
+	for _, key := range failing {
+		errs = append(errs, FieldError{Key: key, Rule: g.rule, Param: g.param(key)})
+	}
+
+	return errs
+}
+
+// param returns the parameter reported along the rule of the group for key: the key and value it depends on for
+// required_if, as the validator does, and the other keys of the group otherwise.
+func (g Group) param(key string) string {
+	if g.rule == "required_if" {
+		return g.when + " " + g.value
+	}
+
+	others := slices.DeleteFunc(slices.Clone(g.keys), func(other string) bool { return other == key })
+
+	return strings.Join(others, " ")
+}
+
+// groupErrors checks groups against cfg, a pointer to the configuration struct whose leaf fields are fields. It
+// returns an error when a group names a key cfg lacks.
+func groupErrors(cfg any, fields []configField, groups []Group) (ValidationErrors, error) {
+	root := reflect.ValueOf(cfg).Elem()
+
+	within := func(key string) []configField {
+		var matching []configField
+
+		for _, f := range fields {
+			if f.key() == key || strings.HasPrefix(f.key(), key+".") {
+				matching = append(matching, f)
+			}
+		}
+
+		return matching
+	}
+
+	isSet := func(key string) bool {
+		for _, f := range within(key) {
+			if v, ok := f.value(root); ok && !v.IsZero() {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	value := func(key string) string {
+		for _, f := range fields {
+			if v, ok := f.value(root); ok && f.key() == key {
+				return fmt.Sprint(v.Interface())
+			}
+		}
+
+		return ""
+	}
+
+	var errs ValidationErrors
+
+	for _, g := range groups {
+		keys := g.keys
+		if g.when != "" {
+			keys = append([]string{g.when}, keys...)
+		}
+
+		for _, key := range keys {
+			if len(within(key)) == 0 {
+				return nil, fmt.Errorf("%w: group %q names unknown key %s", ErrConfig, g.String(), key)
+			}
+		}
+
+		errs = append(errs, g.check(isSet, value)...)
+	}
+
+	return errs, nil
+}
@@ -0,0 +1,118 @@
+package snout_test
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/chiguirez/snout/v3"
+)
+
+type groupConfig struct {
+	TLS struct {
+		CertFile string `snout:"cert_file"`
+		KeyFile  string `snout:"key_file"`
+		ACME     struct {
+			Email string `snout:"email"`
+		} `snout:"acme"`
+	} `snout:"tls"`
+	Auth struct {
+		Mode     string `snout:"mode" default:"none"`
+		ClientID string `snout:"client_id"`
+	} `snout:"auth"`
+	Debug bool `snout:"debug"`
+	Quiet bool `snout:"quiet"`
+}
+
+func (s *snoutSuite) TestGroups() {
+	groups := snout.WithGroups(
+		snout.ExactlyOneOf("tls.cert_file", "tls.acme"),
+		snout.RequiredTogether("tls.cert_file", "tls.key_file"),
+		snout.RequiredIf("auth.mode", "oauth", "auth.client_id"),
+		snout.MutuallyExclusive("debug", "quiet"),
+	)
+
+	s.Run("Given a config Struct with groups of keys", func() {
+		kernel := snout.Kernel[groupConfig]{RunE: func(context.Context, groupConfig) error { return nil }}
+
+		s.Run("When the Kernel is initialized with keys meeting every group", func() {
+			err := kernel.Bootstrap(context.TODO(), groups, snout.WithArgs(
+				"--tls.acme.email=ops@example.com", "--auth.mode=oauth", "--auth.client_id=orders", "--debug",
+			)).Initialize()
+
+			s.Run("Then it succeeds", func() {
+				s.Require().NoError(err)
+			})
+		})
+
+		s.Run("When the Kernel is initialized without any of the keys of an exactly one group", func() {
+			err := kernel.Bootstrap(context.TODO(), groups, snout.WithArgs()).Initialize()
+
+			s.Run("Then every key of the group is reported", func() {
+				var errs snout.ValidationErrors
+				s.Require().ErrorAs(err, &errs)
+				s.Require().Equal(snout.ValidationErrors{
+					{Key: "tls.cert_file", Rule: "exactly_one_of", Param: "tls.acme"},
+					{Key: "tls.acme", Rule: "exactly_one_of", Param: "tls.cert_file"},
+				}, errs)
+			})
+		})
+
+		s.Run("When the Kernel is initialized with keys breaking every group", func() {
+			err := kernel.Bootstrap(context.TODO(), groups, snout.WithArgs(
+				"--tls.cert_file=cert.pem", "--tls.acme.email=ops@example.com", "--auth.mode=oauth", "--debug",
+				"--quiet",
+			)).Initialize()
+
+			s.Run("Then the failing keys are reported by their snout paths", func() {
+				var errs snout.ValidationErrors
+				s.Require().ErrorAs(err, &errs)
+				s.Require().Equal(snout.ValidationErrors{
+					{Key: "tls.cert_file", Rule: "exactly_one_of", Param: "tls.acme"},
+					{Key: "tls.acme", Rule: "exactly_one_of", Param: "tls.cert_file"},
+					{Key: "tls.key_file", Rule: "required_together", Param: "tls.cert_file"},
+					{Key: "auth.client_id", Rule: "required_if", Param: "auth.mode oauth"},
+					{Key: "debug", Rule: "mutually_exclusive", Param: "quiet"},
+					{Key: "quiet", Rule: "mutually_exclusive", Param: "debug"},
+				}, errs)
+			})
+		})
+
+		s.Run("When a group names a key the config Struct lacks", func() {
+			err := kernel.Bootstrap(context.TODO(), snout.WithGroups(snout.RequiredTogether("tls.cert_file", "tls.ca")),
+				snout.WithArgs()).Initialize()
+
+			s.Run("Then it fails naming the key", func() {
+				s.Require().ErrorIs(err, snout.ErrConfig)
+				s.Require().ErrorContains(err, "names unknown key tls.ca")
+			})
+		})
+
+		s.Run("When its schema is written", func() {
+			var buf bytes.Buffer
+
+			err := kernel.WriteReference(&buf, snout.FormatSchema, groups)
+
+			s.Run("Then the groups are listed after the keys", func() {
+				s.Require().NoError(err)
+				s.Require().Contains(buf.String(), `
+GROUPS
+exactly one of tls.cert_file, tls.acme must be set
+tls.cert_file, tls.key_file must be set together
+auth.client_id required when auth.mode is oauth
+at most one of debug, quiet may be set
+`)
+			})
+		})
+
+		s.Run("When its Markdown reference is written", func() {
+			var buf bytes.Buffer
+
+			err := kernel.WriteReference(&buf, snout.FormatMarkdown, groups)
+
+			s.Run("Then the groups are listed under their own heading", func() {
+				s.Require().NoError(err)
+				s.Require().Contains(buf.String(), "\n## Groups\n\n- exactly one of tls.cert_file, tls.acme must be set\n")
+			})
+		})
+	})
+}
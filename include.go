@@ -0,0 +1,184 @@
+package snout
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// ErrIncludeCycle is an error indicating config files including each other, directly or through other files.
+var ErrIncludeCycle = errors.New("config include cycle")
+
+const (
+	// includeKey is the top level key of a config file listing the files it is based on.
+	includeKey = "include"
+	// refKey is the key of a map replaced with the content of the file it names.
+	refKey = "$ref"
+)
+
+// resolveIncludes returns values, a config document read from a file in dir, with its include and $ref directives
+// replaced by the content of the files they name. Relative paths are looked up next to the including file, then in
+// folder, the config folder. chain lists the files including the document, for cycle detection.
+//
+// The files listed by include are merged in order, each overriding the ones before, and the document overrides them
+// all. A map holding a $ref key is replaced with the content of the file it names, its other keys overriding it.
+func resolveIncludes(values map[string]any, dir, folder string, chain []string) (map[string]any, error) {
+	resolved, err := resolveRefs(values, dir, folder, chain)
+	if err != nil {
+		return nil, err
+	}
+
+	values = resolved.(map[string]any)
+
+	includes, err := includePaths(values[includeKey])
+	if err != nil {
+		return nil, err
+	}
+
+	delete(values, includeKey)
+
+	if len(includes) == 0 {
+		return values, nil
+	}
+
+	merged := map[string]any{}
+
+	for _, include := range includes {
+		included, err := readIncluded(include, dir, folder, chain)
+		if err != nil {
+			return nil, err
+		}
+
+		for key, value := range included {
+			mergeNested(merged, []string{key}, value)
+		}
+	}
+
+	for key, value := range values {
+		mergeNested(merged, []string{key}, value)
+	}
+
+	return merged, nil
+}
+
+// resolveRefs replaces every map within value holding a $ref key with the content of the file it names.
+func resolveRefs(value any, dir, folder string, chain []string) (any, error) {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, child := range v {
+			resolved, err := resolveRefs(child, dir, folder, chain)
+			if err != nil {
+				return nil, err
+			}
+
+			v[key] = resolved
+		}
+
+		ref, ok := v[refKey]
+		if !ok {
+			return v, nil
+		}
+
+		name, ok := ref.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s must name a file, got %v", refKey, ref)
+		}
+
+		delete(v, refKey)
+
+		included, err := readIncluded(name, dir, folder, chain)
+		if err != nil {
+			return nil, err
+		}
+
+		for key, child := range v {
+			mergeNested(included, []string{key}, child)
+		}
+
+		return included, nil
+	case []any:
+		for i, item := range v {
+			resolved, err := resolveRefs(item, dir, folder, chain)
+			if err != nil {
+				return nil, err
+			}
+
+			v[i] = resolved
+		}
+
+		return v, nil
+	default:
+		return value, nil
+	}
+}
+
+// includePaths returns the files listed by the value of an include key, a single path or a list of them.
+func includePaths(value any) ([]string, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []string{v}, nil
+	case []any:
+		paths := make([]string, 0, len(v))
+
+		for _, item := range v {
+			path, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s must list files, got %v", includeKey, item)
+			}
+
+			paths = append(paths, path)
+		}
+
+		return paths, nil
+	default:
+		return nil, fmt.Errorf("%s must list files, got %v", includeKey, value)
+	}
+}
+
+// readIncluded reads the config file named by an include or $ref directive of a document in dir, with its own
+// directives resolved, failing with ErrIncludeCycle when it is already being included.
+func readIncluded(name, dir, folder string, chain []string) (map[string]any, error) {
+	path, err := findIncluded(name, dir, folder)
+	if err != nil {
+		return nil, err
+	}
+
+	if slices.Contains(chain, path) {
+		return nil, fmt.Errorf("%w: %s", ErrIncludeCycle, strings.Join(append(slices.Clone(chain), path), " -> "))
+	}
+
+	values, err := readConfigFile(path, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return resolveIncludes(values, filepath.Dir(path), folder, append(slices.Clone(chain), path))
+}
+
+// findIncluded returns the absolute path of the file named by an include or $ref directive of a document in dir,
+// looked up in dir first and then in folder.
+func findIncluded(name, dir, folder string) (string, error) {
+	if filepath.IsAbs(name) {
+		return filepath.Clean(name), nil
+	}
+
+	dirs := slices.Compact([]string{filepath.Clean(dir), filepath.Clean(folder)})
+
+	for _, d := range dirs {
+		path, err := filepath.Abs(filepath.Join(d, name))
+		if err != nil {
+			return "", err
+		}
+
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, nil
+		}
+	}
+
+	return "", fmt.Errorf("included config file %s not found in %s", name, strings.Join(dirs, ", "))
+}
@@ -0,0 +1,91 @@
+package snout_test
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/chiguirez/snout/v3"
+)
+
+type includeConfig struct {
+	Service struct {
+		Name string `snout:"name"`
+	} `snout:"service"`
+	Logging struct {
+		Level  string `snout:"level"`
+		Format string `snout:"format"`
+	} `snout:"logging"`
+	Tracing struct {
+		Endpoint   string  `snout:"endpoint"`
+		SampleRate float64 `snout:"sample_rate"`
+	} `snout:"tracing"`
+	Admin struct {
+		Host    string        `snout:"host"`
+		Port    int           `snout:"port"`
+		Timeout time.Duration `snout:"timeout"`
+	} `snout:"admin"`
+}
+
+func (s *snoutSuite) TestIncludes() {
+	bootstrap := func(opts ...snout.Options) (includeConfig, error) {
+		cfgChan := make(chan includeConfig, 1)
+
+		kernel := snout.Kernel[includeConfig]{RunE: func(_ context.Context, config includeConfig) error {
+			cfgChan <- config
+
+			return nil
+		}}
+
+		if err := kernel.Bootstrap(context.TODO(), append(opts, snout.WithArgs())...).Initialize(); err != nil {
+			return includeConfig{}, err
+		}
+
+		return <-cfgChan, nil
+	}
+
+	s.Run("Given a config file including shared files", func() {
+		s.Run("When the Kernel is initialized", func() {
+			cfg, err := bootstrap(snout.WithServiceName("service"), snout.WithEnvVarFolderLocation("./testdata/include"))
+
+			s.Run("Then included files are merged beneath it in order", func() {
+				s.Require().NoError(err)
+				s.Require().Equal("orders", cfg.Service.Name)
+				s.Require().Equal("debug", cfg.Logging.Level)
+				s.Require().Equal("json", cfg.Logging.Format)
+				s.Require().Equal("http://jaeger:4317", cfg.Tracing.Endpoint)
+				s.Require().Equal(0.5, cfg.Tracing.SampleRate)
+			})
+
+			s.Run("Then $ref sections are read from their files, including files from the config folder", func() {
+				s.Require().Equal("0.0.0.0", cfg.Admin.Host)
+				s.Require().Equal(9091, cfg.Admin.Port)
+				s.Require().Equal(5*time.Second, cfg.Admin.Timeout)
+			})
+		})
+	})
+
+	s.Run("Given config files including each other", func() {
+		s.Run("When the Kernel is initialized", func() {
+			_, err := bootstrap(snout.WithConfigFile("./testdata/include/cycle/a.yaml"))
+
+			s.Run("Then it fails listing the cycle", func() {
+				s.Require().ErrorIs(err, snout.ErrIncludeCycle)
+				s.Require().ErrorContains(err, "b.yaml -> ")
+				s.Require().True(strings.HasSuffix(err.Error(), "a.yaml"))
+			})
+		})
+	})
+
+	s.Run("Given a config document including a missing file", func() {
+		s.Run("When the Kernel is initialized", func() {
+			_, err := bootstrap(snout.WithEnvVarFolderLocation("./testdata/include"),
+				snout.WithConfigReader(strings.NewReader("include: missing.yaml\n"), snout.FormatYAML))
+
+			s.Run("Then it fails naming the file", func() {
+				s.Require().ErrorIs(err, snout.ErrConfig)
+				s.Require().ErrorContains(err, "included config file missing.yaml not found in testdata/include")
+			})
+		})
+	})
+}
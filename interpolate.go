@@ -0,0 +1,202 @@
+package snout
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// ErrInterpolation is an error indicating a ${...} reference in a config value could not be expanded.
+var ErrInterpolation = errors.New("interpolation error")
+
+// interpolator expands ${...} references in the config values held by a viper instance.
+//
+// ${name} resolves to the value of the config key name when T declares it, and to the env var name otherwise.
+// ${name:-fallback} resolves to fallback when name is unset or empty, and $${ is written as a literal ${. Decrypted
+// values are taken as they are, as plaintext may well hold ${, and errors never quote a value, as it may be a secret.
+type interpolator struct {
+	v         *viper.Viper
+	keys      map[string]bool
+	decrypted map[string]bool
+	lookupEnv func(string) (string, bool)
+	resolved  map[string]any
+	resolving []string
+}
+
+// interpolate expands the references in the values of every field but the decrypted ones and stores the results back
+// into v.
+func interpolate(v *viper.Viper, fields []configField, lookupEnv func(string) (string, bool), decrypted map[string]bool,
+) error {
+	in := &interpolator{
+		v:         v,
+		keys:      map[string]bool{},
+		decrypted: decrypted,
+		lookupEnv: lookupEnv,
+		resolved:  map[string]any{},
+	}
+
+	for _, f := range fields {
+		in.keys[f.key()] = true
+	}
+
+	for _, f := range fields {
+		if decrypted[f.key()] || !hasReference(v.Get(f.key())) {
+			continue
+		}
+
+		value, err := in.resolve(f.key())
+		if err != nil {
+			return err
+		}
+
+		v.Set(f.key(), value)
+	}
+
+	return nil
+}
+
+// hasReference reports whether a config value, or any item of a list value, holds a ${...} reference.
+func hasReference(value any) bool {
+	switch v := value.(type) {
+	case string:
+		return strings.Contains(v, "${")
+	case []any:
+		for _, item := range v {
+			if hasReference(item) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// resolve returns the value of key with its references expanded, or as it is for decrypted keys.
+func (in *interpolator) resolve(key string) (any, error) {
+	if value, ok := in.resolved[key]; ok {
+		return value, nil
+	}
+
+	if in.decrypted[key] {
+		return in.v.Get(key), nil
+	}
+
+	for i, resolving := range in.resolving {
+		if resolving == key {
+			cycle := append(append([]string{}, in.resolving[i:]...), key)
+
+			return nil, fmt.Errorf("%w: %s: reference cycle %s", ErrInterpolation, key, strings.Join(cycle, " -> "))
+		}
+	}
+
+	in.resolving = append(in.resolving, key)
+	defer func() { in.resolving = in.resolving[:len(in.resolving)-1] }()
+
+	value := in.v.Get(key)
+
+	switch raw := value.(type) {
+	case string:
+		expanded, err := in.expand(key, raw)
+		if err != nil {
+			return nil, err
+		}
+
+		value = expanded
+	case []any:
+		items := make([]any, 0, len(raw))
+
+		for _, item := range raw {
+			s, ok := item.(string)
+			if !ok {
+				items = append(items, item)
+
+				continue
+			}
+
+			expanded, err := in.expand(key, s)
+			if err != nil {
+				return nil, err
+			}
+
+			items = append(items, expanded)
+		}
+
+		value = items
+	}
+
+	in.resolved[key] = value
+
+	return value, nil
+}
+
+// expand expands the references found in s, the value of key.
+func (in *interpolator) expand(key, s string) (string, error) {
+	if !strings.Contains(s, "${") {
+		return s, nil
+	}
+
+	var out strings.Builder
+
+	for {
+		start := strings.Index(s, "${")
+		if start < 0 {
+			out.WriteString(s)
+
+			return out.String(), nil
+		}
+
+		if start > 0 && s[start-1] == '$' {
+			out.WriteString(s[:start])
+			out.WriteString("{")
+			s = s[start+2:]
+
+			continue
+		}
+
+		end := strings.IndexByte(s[start:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("%w: %s: unterminated reference", ErrInterpolation, key)
+		}
+
+		value, err := in.reference(key, s[start+2:start+end])
+		if err != nil {
+			return "", err
+		}
+
+		out.WriteString(s[:start])
+		out.WriteString(value)
+		s = s[start+end+1:]
+	}
+}
+
+// reference returns the value of a single reference found in the value of key.
+func (in *interpolator) reference(key, ref string) (string, error) {
+	name, fallback, hasFallback := strings.Cut(ref, ":-")
+
+	var (
+		value string
+		set   bool
+	)
+
+	if in.keys[name] {
+		resolved, err := in.resolve(name)
+		if err != nil {
+			return "", err
+		}
+
+		value, set = envString(resolved), in.v.IsSet(name)
+	} else {
+		value, set = in.lookupEnv(name)
+	}
+
+	switch {
+	case hasFallback && value == "":
+		return fallback, nil
+	case !set:
+		return "", fmt.Errorf("%w: %s: ${%s} is not set", ErrInterpolation, key, name)
+	default:
+		return value, nil
+	}
+}
@@ -0,0 +1,172 @@
+package snout_test
+
+import (
+	"context"
+
+	"github.com/chiguirez/snout/v3"
+)
+
+func (s *snoutSuite) TestInterpolation() {
+	s.Run("Given a YAML file referencing env vars and other config keys", func() {
+		type stubConfig struct {
+			Kafka struct {
+				Host    string `snout:"host"`
+				Port    int    `snout:"port"`
+				Address string `snout:"address"`
+				Topic   string `snout:"topic"`
+				Literal string `snout:"literal"`
+			} `snout:"kafka"`
+		}
+
+		s.T().Setenv("INTERP_TOPIC", "orders")
+
+		s.Run("When Kernel is Initialized", func() {
+			cfgChan := make(chan stubConfig, 1)
+
+			kernel := snout.Kernel[stubConfig]{RunE: func(_ context.Context, config stubConfig) error {
+				cfgChan <- config
+
+				return nil
+			}}
+
+			err := kernel.Bootstrap(
+				context.TODO(),
+				snout.WithServiceName("INTERPOLATE"),
+				snout.WithEnvVarFolderLocation("./testdata/interpolate/"),
+			).Initialize()
+			s.Require().NoError(err)
+
+			s.Run("Then references are expanded", func() {
+				config := <-cfgChan
+				s.Require().Equal("localhost", config.Kafka.Host)
+				s.Require().Equal(9092, config.Kafka.Port)
+				s.Require().Equal("localhost:9092", config.Kafka.Address)
+				s.Require().Equal("orders", config.Kafka.Topic)
+				s.Require().Equal("${kafka.host}", config.Kafka.Literal)
+			})
+		})
+	})
+}
+
+func (s *snoutSuite) TestInterpolationUnsetVar() {
+	s.Run("Given a YAML file referencing an unset env var", func() {
+		type stubConfig struct {
+			Kafka struct {
+				Topic string `snout:"topic"`
+			} `snout:"kafka"`
+		}
+
+		s.Run("When the configuration is Checked", func() {
+			kernel := snout.Kernel[stubConfig]{}
+
+			err := kernel.Bootstrap(
+				context.TODO(),
+				snout.WithServiceName("INTERPOLATE"),
+				snout.WithEnvVarFolderLocation("./testdata/interpolate/"),
+			).Check()
+
+			s.Run("Then the error names the key and the variable", func() {
+				s.Require().ErrorIs(err, snout.ErrConfig)
+				s.Require().ErrorIs(err, snout.ErrInterpolation)
+				s.Require().ErrorContains(err, "kafka.topic: ${INTERP_TOPIC} is not set")
+			})
+		})
+	})
+}
+
+func (s *snoutSuite) TestInterpolationCycle() {
+	s.Run("Given a YAML file with keys referencing each other", func() {
+		type stubConfig struct {
+			A string `snout:"a"`
+			B string `snout:"b"`
+		}
+
+		s.Run("When the configuration is Checked", func() {
+			kernel := snout.Kernel[stubConfig]{}
+
+			err := kernel.Bootstrap(
+				context.TODO(),
+				snout.WithServiceName("CYCLE"),
+				snout.WithEnvVarFolderLocation("./testdata/interpolate/"),
+			).Check()
+
+			s.Run("Then the cycle is reported", func() {
+				s.Require().ErrorIs(err, snout.ErrInterpolation)
+				s.Require().ErrorContains(err, "a: reference cycle a -> b -> a")
+			})
+		})
+	})
+}
+
+func (s *snoutSuite) TestInterpolationSecret() {
+	s.Run("Given a secret field referencing an env var", func() {
+		type stubConfig struct {
+			DB struct {
+				Password string `snout:"password" secret:"true"`
+				User     string `snout:"user"`
+			} `snout:"db"`
+		}
+
+		lookupEnv := func(password string) snout.Options {
+			return snout.WithLookupEnv(func(name string) (string, bool) {
+				switch name {
+				case "APP_DB_PASSWORD":
+					return password, true
+				case "DB_PASSWORD":
+					return "hunter2", true
+				}
+
+				return "", false
+			})
+		}
+
+		s.Run("When Kernel is Initialized", func() {
+			cfgChan := make(chan stubConfig, 1)
+
+			kernel := snout.Kernel[stubConfig]{RunE: func(_ context.Context, config stubConfig) error {
+				cfgChan <- config
+
+				return nil
+			}}
+
+			err := kernel.Bootstrap(context.TODO(), snout.WithArgs(), snout.WithEnvVarPrefix("APP"),
+				lookupEnv("${DB_PASSWORD}")).Initialize()
+			s.Require().NoError(err)
+
+			s.Run("Then the secret is expanded like any other value", func() {
+				s.Require().Equal("hunter2", (<-cfgChan).DB.Password)
+			})
+		})
+
+		s.Run("When the secret holds an unterminated reference", func() {
+			kernel := snout.Kernel[stubConfig]{}
+
+			err := kernel.Bootstrap(context.TODO(), snout.WithArgs(), snout.WithEnvVarPrefix("APP"),
+				lookupEnv("s3cr${et")).Check()
+
+			s.Run("Then the error names the key without its value", func() {
+				s.Require().ErrorIs(err, snout.ErrInterpolation)
+				s.Require().ErrorContains(err, "db.password: unterminated reference")
+				s.Require().NotContains(err.Error(), "s3cr")
+			})
+		})
+
+		s.Run("When the secret is encrypted and its plaintext holds ${", func() {
+			cfgChan := make(chan stubConfig, 1)
+
+			kernel := snout.Kernel[stubConfig]{RunE: func(_ context.Context, config stubConfig) error {
+				cfgChan <- config
+
+				return nil
+			}}
+
+			err := kernel.Bootstrap(context.TODO(), snout.WithArgs(), snout.WithEnvVarPrefix("APP"),
+				lookupEnv("ENC[te{$rc3s]"), snout.WithDecryptor(reverseDecryptor{})).Initialize()
+			s.Require().NoError(err)
+
+			s.Run("Then the plaintext is loaded as it is", func() {
+				s.Require().Equal("s3cr${et", (<-cfgChan).DB.Password)
+			})
+		})
+	})
+}
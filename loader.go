@@ -0,0 +1,450 @@
+package snout
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/spf13/pflag"
+)
+
+// Loader is implemented by configuration types with a loader generated by snoutgen. The kernel loads and validates
+// such types through it, without walking, binding flags to or decoding into T through reflection:
+//
+//	//go:generate go run github.com/chiguirez/snout/v3/cmd/snoutgen -type Config
+type Loader interface {
+	// SnoutKeys returns every leaf key of the configuration in declaration order.
+	SnoutKeys() []Key
+	// SnoutDecode decodes the resolved value of every key, as returned by get, into the configuration.
+	SnoutDecode(get func(key string) any) error
+	// SnoutValidate validates the configuration against its validate tags.
+	SnoutValidate() ValidationErrors
+}
+
+// Key describes a leaf key of a configuration type for a generated Loader.
+type Key struct {
+	// Path is the dotted key, e.g. kafka.broker_address.
+	Path string
+	// Field is the name of the struct field holding the key.
+	Field string
+	// Index is the index sequence of the field from the root struct.
+	Index []int
+	// Type is the type of the field.
+	Type reflect.Type
+	// Tag is the struct tag of the field.
+	Tag reflect.StructTag
+}
+
+// fieldsOf returns the leaf fields of cfg, a pointer to a configuration struct, from its generated loader when it
+// has one and by walking its type with keys named by n otherwise.
+func fieldsOf(cfg any, n naming) []configField {
+	loader, ok := cfg.(Loader)
+	if !ok {
+		return configFields(reflect.TypeOf(cfg).Elem(), n)
+	}
+
+	keys := loader.SnoutKeys()
+	fields := make([]configField, 0, len(keys))
+
+	for _, key := range keys {
+		fields = append(fields, configField{
+			path:  key.Path,
+			field: reflect.StructField{Name: key.Field, Type: key.Type, Tag: key.Tag, Index: key.Index},
+			index: key.Index,
+		})
+	}
+
+	return fields
+}
+
+// defineFlags defines a flag for every field not excluded from flags, in place of the flags gpflag derives from the
+// configuration struct. Values are kept as text, or lists of text for slices, and decoded by the generated loader.
+// Collections are left to defineCollectionFlags.
+func defineFlags(flagSet *pflag.FlagSet, fields []configField) {
+	for _, f := range fields {
+		if f.flagName() == "" || f.collection() {
+			continue
+		}
+
+		if f.typ().Kind() == reflect.Slice {
+			flagSet.StringSliceP(f.flagName(), f.shorthand(), nil, f.description())
+
+			continue
+		}
+
+		flagSet.StringP(f.flagName(), f.shorthand(), "", f.description())
+
+		if f.typ().Kind() == reflect.Bool || f.typ() == reflect.TypeOf(Feature{}) {
+			flagSet.Lookup(f.flagName()).NoOptDefVal = "true"
+		}
+	}
+}
+
+// DecodeString decodes a config value into a string, for generated loaders.
+func DecodeString(raw any) (string, error) {
+	switch v := raw.(type) {
+	case bool:
+		if v {
+			return "1", nil
+		}
+
+		return "0", nil
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	default:
+		return envString(raw), nil
+	}
+}
+
+// DecodeBool decodes a config value into a bool, for generated loaders.
+func DecodeBool(raw any) (bool, error) {
+	switch v := raw.(type) {
+	case nil:
+		return false, nil
+	case bool:
+		return v, nil
+	case string:
+		if v == "" {
+			return false, nil
+		}
+
+		return strconv.ParseBool(v)
+	default:
+		f, err := DecodeFloat(raw, 64)
+
+		return f != 0, err
+	}
+}
+
+// DecodeInt decodes a config value into a signed integer of bitSize bits, for generated loaders.
+func DecodeInt(raw any, bitSize int) (int64, error) {
+	var n int64
+
+	switch v := raw.(type) {
+	case nil:
+		return 0, nil
+	case string:
+		if v == "" {
+			return 0, nil
+		}
+
+		return strconv.ParseInt(v, 0, bitSize)
+	case bool:
+		if v {
+			n = 1
+		}
+	case int:
+		n = int64(v)
+	case int8:
+		n = int64(v)
+	case int16:
+		n = int64(v)
+	case int32:
+		n = int64(v)
+	case int64:
+		n = v
+	case uint:
+		n = int64(v)
+	case uint8:
+		n = int64(v)
+	case uint16:
+		n = int64(v)
+	case uint32:
+		n = int64(v)
+	case uint64:
+		n = int64(v)
+	case float32:
+		n = int64(v)
+	case float64:
+		n = int64(v)
+	case time.Duration:
+		n = int64(v)
+	default:
+		return 0, fmt.Errorf("cannot decode %T into an integer", raw)
+	}
+
+	if bitSize < 64 && (n < -1<<(bitSize-1) || n > 1<<(bitSize-1)-1) {
+		return 0, fmt.Errorf("%d out of range for a %d bit integer", n, bitSize)
+	}
+
+	return n, nil
+}
+
+// DecodeUint decodes a config value into an unsigned integer of bitSize bits, for generated loaders.
+func DecodeUint(raw any, bitSize int) (uint64, error) {
+	if s, ok := raw.(string); ok {
+		if s == "" {
+			return 0, nil
+		}
+
+		return strconv.ParseUint(s, 0, bitSize)
+	}
+
+	n, err := DecodeInt(raw, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	if n < 0 || bitSize < 64 && uint64(n) > 1<<bitSize-1 {
+		return 0, fmt.Errorf("%d out of range for a %d bit unsigned integer", n, bitSize)
+	}
+
+	return uint64(n), nil
+}
+
+// DecodeFloat decodes a config value into a float of bitSize bits, for generated loaders.
+func DecodeFloat(raw any, bitSize int) (float64, error) {
+	switch v := raw.(type) {
+	case nil:
+		return 0, nil
+	case string:
+		if v == "" {
+			return 0, nil
+		}
+
+		return strconv.ParseFloat(v, bitSize)
+	case float32:
+		return float64(v), nil
+	case float64:
+		if bitSize == 32 && math.Abs(v) > math.MaxFloat32 {
+			return 0, fmt.Errorf("%g out of range for a 32 bit float", v)
+		}
+
+		return v, nil
+	case bool:
+		if v {
+			return 1, nil
+		}
+
+		return 0, nil
+	default:
+		n, err := DecodeInt(raw, 64)
+
+		return float64(n), err
+	}
+}
+
+// DecodeDuration decodes a config value into a time.Duration, text being parsed with time.ParseDuration and numbers
+// taken as nanoseconds, for generated loaders.
+func DecodeDuration(raw any) (time.Duration, error) {
+	if s, ok := raw.(string); ok {
+		if s == "" {
+			return 0, nil
+		}
+
+		return time.ParseDuration(s)
+	}
+
+	n, err := DecodeInt(raw, 64)
+
+	return time.Duration(n), err
+}
+
+// DecodeFeature decodes a config value into a Feature, for generated loaders.
+func DecodeFeature(raw any) (Feature, error) {
+	if raw == nil {
+		return Feature{}, nil
+	}
+
+	feature, err := featureHookFunc(reflect.TypeOf(raw), reflect.TypeOf(Feature{}), raw)
+	if err != nil {
+		return Feature{}, err
+	}
+
+	return feature.(Feature), nil
+}
+
+// DecodeList decodes a config value into the items of a slice, for generated loaders to decode one by one. A single
+// value is taken as a list of one item.
+func DecodeList(raw any) ([]any, error) {
+	switch v := raw.(type) {
+	case nil:
+		return nil, nil
+	case []any:
+		return v, nil
+	case []string:
+		items := make([]any, 0, len(v))
+		for _, item := range v {
+			items = append(items, item)
+		}
+
+		return items, nil
+	default:
+		return []any{raw}, nil
+	}
+}
+
+// ValidateValue checks value, the value of key, against rules written as in a validate tag, for generated loaders.
+// value is nil for unset pointers. As the validator does, it reports the first rule value fails. The rules snout checks
+// natively are required, omitempty, len, min, max, eq, ne, gt, gte, lt, lte and oneof on strings, numbers, durations
+// and slices; any other rule is checked by the validator.
+func ValidateValue(key string, value any, rules string) ValidationErrors {
+	if strings.ContainsAny(rules, "|") || strings.Contains(rules, "dive") {
+		return validateVar(key, value, rules)
+	}
+
+	for _, rule := range strings.Split(rules, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+
+		switch name {
+		case "":
+			continue
+		case "omitempty":
+			if isZero(value) {
+				return nil
+			}
+
+			continue
+		case "required":
+			if isZero(value) {
+				return ValidationErrors{{Key: key, Rule: name}}
+			}
+
+			continue
+		}
+
+		if value == nil {
+			continue
+		}
+
+		ok, err := checkRule(value, name, param)
+		if errors.Is(err, errUnsupportedRule) {
+			if errs := validateVar(key, value, rule); len(errs) > 0 {
+				return errs
+			}
+
+			continue
+		}
+
+		if err != nil || !ok {
+			return ValidationErrors{{Key: key, Rule: name, Param: param}}
+		}
+	}
+
+	return nil
+}
+
+// errUnsupportedRule reports a rule ValidateValue leaves to the validator.
+var errUnsupportedRule = errors.New("unsupported rule")
+
+// checkRule reports whether value passes the rule name with param.
+func checkRule(value any, name, param string) (bool, error) {
+	switch name {
+	case "len", "eq", "ne", "min", "gte", "max", "lte", "gt", "lt":
+	case "oneof":
+		for _, candidate := range strings.Fields(param) {
+			if envString(value) == candidate {
+				return true, nil
+			}
+		}
+
+		return false, nil
+	default:
+		return false, errUnsupportedRule
+	}
+
+	var (
+		actual, expected float64
+		err              error
+	)
+
+	switch v := value.(type) {
+	case string:
+		actual = float64(utf8.RuneCountInString(v))
+		expected, err = strconv.ParseFloat(param, 64)
+
+		if name == "eq" || name == "ne" {
+			return (v == param) == (name == "eq"), nil
+		}
+	case time.Duration:
+		actual = float64(v)
+
+		var d time.Duration
+		d, err = time.ParseDuration(param)
+		expected = float64(d)
+	case bool:
+		if name != "eq" && name != "ne" {
+			return false, errUnsupportedRule
+		}
+
+		b, err := strconv.ParseBool(param)
+
+		return (v == b) == (name == "eq"), err
+	case []string:
+		actual = float64(len(v))
+		expected, err = strconv.ParseFloat(param, 64)
+	default:
+		actual, err = DecodeFloat(value, 64)
+		if err != nil {
+			return false, errUnsupportedRule
+		}
+
+		expected, err = strconv.ParseFloat(param, 64)
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	switch name {
+	case "len", "eq":
+		return actual == expected, nil
+	case "ne":
+		return actual != expected, nil
+	case "min", "gte":
+		return actual >= expected, nil
+	case "max", "lte":
+		return actual <= expected, nil
+	case "gt":
+		return actual > expected, nil
+	case "lt":
+		return actual < expected, nil
+	default:
+		return false, errUnsupportedRule
+	}
+}
+
+// isZero reports whether value is unset or the zero value of its type.
+func isZero(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case bool:
+		return !v
+	case []string:
+		return len(v) == 0
+	case Feature:
+		return v.Percentage() == 0
+	}
+
+	if n, err := DecodeFloat(value, 64); err == nil {
+		return n == 0
+	}
+
+	return reflect.ValueOf(value).IsZero()
+}
+
+// validateVar checks value against rules with the validator, reporting failures under key.
+func validateVar(key string, value any, rules string) ValidationErrors {
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(validator.New().Var(value, rules), &fieldErrs) {
+		return nil
+	}
+
+	errs := make(ValidationErrors, 0, len(fieldErrs))
+	for _, fieldErr := range fieldErrs {
+		errs = append(errs, FieldError{Key: key, Rule: fieldErr.Tag(), Param: fieldErr.Param()})
+	}
+
+	return errs
+}
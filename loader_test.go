@@ -0,0 +1,152 @@
+package snout_test
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/chiguirez/snout/v3"
+)
+
+// loaderConfig implements snout.Loader by hand, as snoutgen would generate it.
+type loaderConfig struct {
+	Topic   string        `snout:"topic" default:"orders"`
+	Timeout time.Duration `snout:"timeout" validate:"gte=1s"`
+	decoded bool
+}
+
+func (c *loaderConfig) SnoutKeys() []snout.Key {
+	return []snout.Key{
+		{Path: "topic", Field: "Topic", Index: []int{0}, Type: reflect.TypeOf(""), Tag: `snout:"topic" default:"orders"`},
+		{Path: "timeout", Field: "Timeout", Index: []int{1}, Type: reflect.TypeOf(time.Duration(0)),
+			Tag: `snout:"timeout" validate:"gte=1s"`},
+	}
+}
+
+func (c *loaderConfig) SnoutDecode(get func(key string) any) error {
+	var err error
+
+	c.decoded = true
+
+	if c.Topic, err = snout.DecodeString(get("topic")); err != nil {
+		return err
+	}
+
+	c.Timeout, err = snout.DecodeDuration(get("timeout"))
+
+	return err
+}
+
+func (c *loaderConfig) SnoutValidate() snout.ValidationErrors {
+	return snout.ValidateValue("timeout", c.Timeout, "gte=1s")
+}
+
+func (s *snoutSuite) TestLoader() {
+	bootstrap := func(args ...string) (loaderConfig, error) {
+		cfgChan := make(chan loaderConfig, 1)
+
+		kernel := snout.Kernel[loaderConfig]{RunE: func(_ context.Context, config loaderConfig) error {
+			cfgChan <- config
+
+			return nil
+		}}
+
+		if err := kernel.Bootstrap(context.TODO(), snout.WithArgs(args...)).Initialize(); err != nil {
+			return loaderConfig{}, err
+		}
+
+		return <-cfgChan, nil
+	}
+
+	s.Run("Given a config Struct implementing Loader", func() {
+		s.Run("When the kernel is initialized with flags", func() {
+			cfg, err := bootstrap("--timeout=2s")
+
+			s.Run("Then the config is decoded through the loader", func() {
+				s.Require().NoError(err)
+				s.Require().True(cfg.decoded)
+				s.Require().Equal("orders", cfg.Topic)
+				s.Require().Equal(2*time.Second, cfg.Timeout)
+			})
+		})
+
+		s.Run("When the config breaks its validate tags", func() {
+			_, err := bootstrap("--timeout=10ms")
+
+			s.Run("Then the loader validation errors are returned", func() {
+				var errs snout.ValidationErrors
+				s.Require().ErrorIs(err, snout.ErrValidation)
+				s.Require().ErrorAs(err, &errs)
+				s.Require().Equal(snout.ValidationErrors{{Key: "timeout", Rule: "gte", Param: "1s"}}, errs)
+			})
+		})
+	})
+}
+
+func (s *snoutSuite) TestDecode() {
+	s.Run("Given config values as read from files, env vars and flags", func() {
+		s.Run("When they are decoded into the types of their keys", func() {
+			s.Run("Then they are converted as by the reflective decoder", func() {
+				str, err := snout.DecodeString(true)
+				s.Require().NoError(err)
+				s.Require().Equal("1", str)
+
+				b, err := snout.DecodeBool("true")
+				s.Require().NoError(err)
+				s.Require().True(b)
+
+				n, err := snout.DecodeInt("0x10", 64)
+				s.Require().NoError(err)
+				s.Require().Equal(int64(16), n)
+
+				u, err := snout.DecodeUint(8080, 16)
+				s.Require().NoError(err)
+				s.Require().Equal(uint64(8080), u)
+
+				f, err := snout.DecodeFloat("0.5", 32)
+				s.Require().NoError(err)
+				s.Require().Equal(0.5, f)
+
+				d, err := snout.DecodeDuration("1m")
+				s.Require().NoError(err)
+				s.Require().Equal(time.Minute, d)
+
+				feature, err := snout.DecodeFeature("25%")
+				s.Require().NoError(err)
+				s.Require().Equal(25.0, feature.Percentage())
+
+				items, err := snout.DecodeList([]string{"a", "b"})
+				s.Require().NoError(err)
+				s.Require().Equal([]any{"a", "b"}, items)
+			})
+		})
+
+		s.Run("When they overflow their type", func() {
+			_, intErr := snout.DecodeInt(300, 8)
+			_, uintErr := snout.DecodeUint(-1, 64)
+
+			s.Run("Then an error is returned", func() {
+				s.Require().Error(intErr)
+				s.Require().Error(uintErr)
+			})
+		})
+	})
+}
+
+func (s *snoutSuite) TestValidateValue() {
+	s.Run("Given values and validate rules", func() {
+		s.Run("When they are checked", func() {
+			s.Run("Then failures are reported under the key, natively or by the validator", func() {
+				s.Require().Empty(snout.ValidateValue("port", 8080, "required,gte=1024,lt=65536"))
+				s.Require().Empty(snout.ValidateValue("pool", nil, "omitempty,max=10"))
+				s.Require().Empty(snout.ValidateValue("dsn", "postgres://db", "required,url"))
+				s.Require().Equal(snout.ValidationErrors{{Key: "name", Rule: "required"}},
+					snout.ValidateValue("name", "", "required,min=3"))
+				s.Require().Equal(snout.ValidationErrors{{Key: "level", Rule: "oneof", Param: "debug info"}},
+					snout.ValidateValue("level", "trace", "oneof=debug info"))
+				s.Require().Equal(snout.ValidationErrors{{Key: "dsn", Rule: "url"}},
+					snout.ValidateValue("dsn", "not a url", "url"))
+			})
+		})
+	})
+}
@@ -0,0 +1,139 @@
+package snout
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// NamingStrategy derives the key segment of a field without name in its tag from the Go field name, as in
+// broker_address for BrokerAddress.
+type NamingStrategy func(fieldName string) string
+
+// SnakeCase names keys in snake_case, as in broker_address or http_port for HTTPPort.
+func SnakeCase(fieldName string) string {
+	return strings.Join(words(fieldName), "_")
+}
+
+// KebabCase names keys in kebab-case, as in broker-address or http-port for HTTPPort.
+func KebabCase(fieldName string) string {
+	return strings.Join(words(fieldName), "-")
+}
+
+// CamelCase names keys in camelCase, as in brokerAddress or httpPort for HTTPPort.
+func CamelCase(fieldName string) string {
+	parts := words(fieldName)
+	for i := 1; i < len(parts); i++ {
+		parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+	}
+
+	return strings.Join(parts, "")
+}
+
+// words splits a Go identifier into lower case words, keeping acronyms and digits together: HTTPPort2 becomes http
+// and port2.
+func words(name string) []string {
+	runes := []rune(name)
+
+	var (
+		parts []string
+		start int
+	)
+
+	for i := 1; i < len(runes); i++ {
+		prev, cur := runes[i-1], runes[i]
+		nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+
+		switch {
+		case cur == '_':
+			parts = append(parts, string(runes[start:i]))
+			start = i + 1
+		case unicode.IsUpper(cur) && (unicode.IsLower(prev) || unicode.IsDigit(prev) || unicode.IsUpper(prev) && nextLower):
+			parts = append(parts, string(runes[start:i]))
+			start = i
+		}
+	}
+
+	parts = append(parts, string(runes[start:]))
+
+	lower := parts[:0]
+
+	for _, part := range parts {
+		if part != "" {
+			lower = append(lower, strings.ToLower(part))
+		}
+	}
+
+	return lower
+}
+
+// WithTagName sets the struct tag keys are named by in KernelOptions, snout by default. Tags such as yaml or json
+// let structs shared with other decoders be loaded as they are: options after a comma are ignored and "-" leaves the
+// field out.
+func WithTagName(name string) Options {
+	return func(kernel *KernelOptions) {
+		kernel.TagName = name
+	}
+}
+
+// WithKeyNaming sets the strategy naming the keys of fields without name in their tag in KernelOptions, such as
+// SnakeCase, so that structs from other packages can be loaded without tags. Untagged fields have an empty key
+// segment by default.
+func WithKeyNaming(strategy NamingStrategy) Options {
+	return func(kernel *KernelOptions) {
+		kernel.KeyNaming = strategy
+	}
+}
+
+// naming names the keys of struct fields after a tag and a naming strategy.
+type naming struct {
+	tag      string
+	strategy NamingStrategy
+}
+
+// namingOf returns the naming configured in options.
+func namingOf(options *KernelOptions) naming {
+	tag := options.TagName
+	if tag == "" {
+		tag = "snout"
+	}
+
+	return naming{tag: tag, strategy: options.KeyNaming}
+}
+
+// isDefault reports whether n names keys by snout tags alone, as gpflag derives flags.
+func (n naming) isDefault() bool {
+	return n.tag == "snout" && n.strategy == nil
+}
+
+// name returns the key segment of field, or false when its tag leaves it out.
+func (n naming) name(field reflect.StructField) (string, bool) {
+	name, _, _ := strings.Cut(field.Tag.Get(n.tag), ",")
+
+	switch {
+	case name == "-":
+		return "", false
+	case name == "" && n.strategy != nil:
+		return n.strategy(field.Name), true
+	default:
+		return name, true
+	}
+}
+
+// matchName matches map keys to struct fields while decoding, fieldName being the name from the tag or the Go
+// field name for untagged fields.
+func (n naming) matchName(mapKey, fieldName string) bool {
+	if strings.EqualFold(mapKey, fieldName) {
+		return true
+	}
+
+	return n.strategy != nil && strings.EqualFold(mapKey, n.strategy(fieldName))
+}
+
+// flattened reports whether field is an embedded struct without name in its tag, whose keys are those of the
+// struct embedding it rather than nested under a key of its own.
+func (n naming) flattened(field reflect.StructField) bool {
+	name, _, _ := strings.Cut(field.Tag.Get(n.tag), ",")
+
+	return field.Anonymous && name == "" && isNestedStruct(field.Type)
+}
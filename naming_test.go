@@ -0,0 +1,140 @@
+package snout_test
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/chiguirez/snout/v3"
+)
+
+// poolOptions stands for a struct from a library, without tags of any kind.
+type poolOptions struct {
+	MaxConns    int `validate:"gte=1"`
+	IdleTimeout time.Duration
+	HTTPProxy   string
+}
+
+type untaggedConfig struct {
+	Pool    poolOptions `snout:"pool"`
+	Service string
+}
+
+type yamlConfig struct {
+	Name    string `yaml:"name" validate:"required"`
+	Port    int    `yaml:"port,omitempty" default:"8080"`
+	Ignored string `yaml:"-"`
+	Kafka   struct {
+		Topic string `yaml:"topic"`
+	} `yaml:"kafka"`
+}
+
+func (s *snoutSuite) TestNamingStrategies() {
+	s.Run("Given Go field names", func() {
+		names := []string{"BrokerAddress", "HTTPPort", "ID", "UserID", "Retries2", "TLS_Cert"}
+
+		s.Run("When keys are named after them", func() {
+			s.Run("Then each strategy splits them into words", func() {
+				snake := make([]string, 0, len(names))
+				kebab := make([]string, 0, len(names))
+				camel := make([]string, 0, len(names))
+
+				for _, name := range names {
+					snake = append(snake, snout.SnakeCase(name))
+					kebab = append(kebab, snout.KebabCase(name))
+					camel = append(camel, snout.CamelCase(name))
+				}
+
+				s.Require().Equal([]string{"broker_address", "http_port", "id", "user_id", "retries2", "tls_cert"}, snake)
+				s.Require().Equal([]string{"broker-address", "http-port", "id", "user-id", "retries2", "tls-cert"}, kebab)
+				s.Require().Equal([]string{"brokerAddress", "httpPort", "id", "userId", "retries2", "tlsCert"}, camel)
+			})
+		})
+	})
+}
+
+func (s *snoutSuite) TestKeyNaming() {
+	env := func(vars map[string]string) snout.Options {
+		return snout.WithLookupEnv(func(name string) (string, bool) {
+			value, ok := vars[name]
+
+			return value, ok
+		})
+	}
+
+	s.Run("Given a config Struct embedding a struct without tags", func() {
+		bootstrap := func(opts ...snout.Options) (untaggedConfig, error) {
+			cfgChan := make(chan untaggedConfig, 1)
+
+			kernel := snout.Kernel[untaggedConfig]{RunE: func(_ context.Context, config untaggedConfig) error {
+				cfgChan <- config
+
+				return nil
+			}}
+
+			opts = append([]snout.Options{snout.WithKeyNaming(snout.SnakeCase), snout.WithEnvVarPrefix("APP")}, opts...)
+			if err := kernel.Bootstrap(context.TODO(), opts...).Initialize(); err != nil {
+				return untaggedConfig{}, err
+			}
+
+			return <-cfgChan, nil
+		}
+
+		s.Run("When it is loaded with snake_case key naming from a config file, env vars and flags", func() {
+			cfg, err := bootstrap(
+				snout.WithConfigReader(strings.NewReader("pool:\n  http_proxy: proxy:3128\nservice: orders\n"),
+					snout.FormatYAML),
+				env(map[string]string{"APP_POOL_MAX_CONNS": "10"}),
+				snout.WithArgs("--pool.idle_timeout=30s"),
+			)
+
+			s.Run("Then untagged fields are read from keys named after them", func() {
+				s.Require().NoError(err)
+				s.Require().Equal(10, cfg.Pool.MaxConns)
+				s.Require().Equal(30*time.Second, cfg.Pool.IdleTimeout)
+				s.Require().Equal("proxy:3128", cfg.Pool.HTTPProxy)
+				s.Require().Equal("orders", cfg.Service)
+			})
+		})
+
+		s.Run("When it breaks a validate tag", func() {
+			_, err := bootstrap(env(map[string]string{"APP_POOL_MAX_CONNS": "0"}), snout.WithArgs())
+
+			s.Run("Then the failing field is reported by its derived key", func() {
+				var errs snout.ValidationErrors
+				s.Require().ErrorAs(err, &errs)
+				s.Require().Equal(snout.ValidationErrors{{Key: "pool.max_conns", Rule: "gte", Param: "1"}}, errs)
+			})
+		})
+	})
+
+	s.Run("Given a config Struct with yaml tags", func() {
+		cfgChan := make(chan yamlConfig, 1)
+
+		kernel := snout.Kernel[yamlConfig]{RunE: func(_ context.Context, config yamlConfig) error {
+			cfgChan <- config
+
+			return nil
+		}}
+
+		s.Run("When it is loaded with the yaml tag name", func() {
+			err := kernel.Bootstrap(context.TODO(),
+				snout.WithTagName("yaml"),
+				snout.WithConfigReader(strings.NewReader("name: orders\nIgnored: set\nkafka:\n  topic: events\n"),
+					snout.FormatYAML),
+				env(map[string]string{"IGNORED": "set"}),
+				snout.WithArgs("--port=9090"),
+			).Initialize()
+
+			s.Run("Then keys are named by yaml tags and fields tagged - are left out", func() {
+				s.Require().NoError(err)
+
+				cfg := <-cfgChan
+				s.Require().Equal("orders", cfg.Name)
+				s.Require().Equal(9090, cfg.Port)
+				s.Require().Equal("events", cfg.Kafka.Topic)
+				s.Require().Empty(cfg.Ignored)
+			})
+		})
+	})
+}
@@ -0,0 +1,39 @@
+package snout
+
+import (
+	"context"
+	"sync"
+)
+
+// readyKey is the context key under which Initialize stores the function marking the kernel ready.
+type readyKey struct{}
+
+// Ready marks the kernel running with ctx as ready, typically once RunE has its listeners up. Only the first call
+// has any effect, and calls with a context not handed out by snout are ignored.
+func Ready(ctx context.Context) {
+	if ready, ok := ctx.Value(readyKey{}).(func()); ok {
+		ready()
+	}
+}
+
+// WithReadyHook sets the function called when RunE marks the kernel ready through Ready in KernelOptions.
+func WithReadyHook(fn func()) Options {
+	return func(kernel *KernelOptions) {
+		kernel.OnReady = fn
+	}
+}
+
+// withReady returns a copy of ctx through which Ready logs the kernel as ready and calls the OnReady hook, once.
+func withReady(ctx context.Context, options *KernelOptions) context.Context {
+	var once sync.Once
+
+	return context.WithValue(ctx, readyKey{}, func() {
+		once.Do(func() {
+			logger.Info("Kernel ready")
+
+			if options.OnReady != nil {
+				options.OnReady()
+			}
+		})
+	})
+}
@@ -0,0 +1,280 @@
+package snout
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// referenceRow is one documented configuration key.
+type referenceRow struct {
+	Key         string
+	Env         string
+	Flag        string
+	Type        string
+	Default     string
+	Validation  string
+	Secret      bool
+	Description string
+}
+
+// WriteReference writes the configuration reference of T, one row per key with its env var, flag, type, default,
+// validation rules, secrecy and desc tag, as a Markdown or HTML table followed by the groups set with WithGroups.
+// FormatSchema writes the key, env var, flag, type and default only, along with the groups, the surface that breaks
+// deployments when it changes.
+func (k *Kernel[T]) WriteReference(w io.Writer, format Format, opts ...Options) error {
+	kernelOpts := NewKernelOptions()
+	for _, opt := range opts {
+		opt(kernelOpts)
+	}
+
+	return writeReference(w, reflect.TypeOf((*T)(nil)).Elem(), format, kernelOpts)
+}
+
+// writeReference renders the reference of t in the given format.
+func writeReference(w io.Writer, t reflect.Type, format Format, options *KernelOptions) error {
+	rows := referenceRows(t, options)
+
+	title := "Configuration"
+	if options.ServiceName != "" {
+		title = options.ServiceName + " configuration"
+	}
+
+	switch format {
+	case FormatMarkdown:
+		return writeMarkdownReference(w, title, rows, options.Groups)
+	case FormatHTML:
+		return htmlReference.Execute(w, struct {
+			Title  string
+			Rows   []referenceRow
+			Groups []Group
+		}{title, rows, options.Groups})
+	case FormatSchema:
+		return writeSchemaReference(w, rows, options.Groups)
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+}
+
+// referenceRows builds the reference rows for every field of t, each collection followed by the keys within its
+// elements.
+func referenceRows(t reflect.Type, options *KernelOptions) []referenceRow {
+	n := namingOf(options)
+	fields := configFields(t, n)
+	rows := make([]referenceRow, 0, len(fields))
+
+	for _, f := range fields {
+		rows = append(rows, fieldRow(f, f.key(), f.envName(options.Env), f.flagUsage()))
+
+		if f.collection() {
+			rows = append(rows, elementRows(f, n, options)...)
+		}
+	}
+
+	return rows
+}
+
+// fieldRow builds the reference row of f documented under key, env and flag, leaving the default of a secret blank.
+func fieldRow(f configField, key, env, flag string) referenceRow {
+	defaultValue, _ := f.defaultValue()
+	if f.secret() {
+		defaultValue = ""
+	}
+
+	return referenceRow{
+		Key:         key,
+		Env:         env,
+		Flag:        flag,
+		Type:        f.typ().String(),
+		Default:     defaultValue,
+		Validation:  f.validation(),
+		Secret:      f.secret(),
+		Description: f.description(),
+	}
+}
+
+// elementRows builds the reference rows of the keys within the elements of f, a collection: upstreams[].host for a
+// list of structs, set by APP_UPSTREAMS_<N>_HOST and --upstreams.host, tenants.<key>.dsn for a map, set by
+// APP_TENANTS_<KEY>_DSN, and queue.type followed by queue[sqs].url for the variants of an interface, set along with
+// the whole section.
+func elementRows(f configField, n naming, options *KernelOptions) []referenceRow {
+	separator := options.Env.keySeparator()
+	env := func(index, suffix string) string {
+		if f.envName(options.Env) == "" {
+			return ""
+		}
+
+		return strings.Join(slices.DeleteFunc([]string{f.envName(options.Env), index, suffix}, func(s string) bool {
+			return s == ""
+		}), separator)
+	}
+
+	var rows []referenceRow
+
+	switch f.typ().Kind() {
+	case reflect.Slice:
+		for _, elem := range elementFields(f, n) {
+			flag := ""
+			if f.flagName() != "" {
+				flag = "--" + f.flagName() + "." + elem.key()
+			}
+
+			rows = append(rows, fieldRow(elem, f.key()+"[]."+elem.key(), env("<N>", elementEnvSuffix(elem, separator)),
+				flag))
+		}
+	case reflect.Map:
+		elems := elementFields(f, n)
+		if len(elems) == 0 {
+			return []referenceRow{{Key: f.key() + ".<key>", Env: env("<KEY>", ""), Type: f.typ().Elem().String()}}
+		}
+
+		for _, elem := range elems {
+			rows = append(rows, fieldRow(elem, f.key()+".<key>."+elem.key(), env("<KEY>", elementEnvSuffix(elem, separator)),
+				""))
+		}
+	case reflect.Interface:
+		variants := options.Variants[f.typ()]
+		names := variantNames(variants)
+
+		rows = append(rows, referenceRow{
+			Key:        f.key() + "." + f.discriminator(),
+			Type:       "string",
+			Validation: "oneof=" + strings.Join(names, " "),
+		})
+
+		for _, name := range names {
+			for _, vf := range configFields(indirectType(variants[name]), n) {
+				rows = append(rows, fieldRow(vf, f.key()+"["+name+"]."+vf.key(), "", ""))
+			}
+		}
+	}
+
+	return rows
+}
+
+// writeMarkdownReference writes rows as a Markdown table, followed by a list of groups.
+func writeMarkdownReference(w io.Writer, title string, rows []referenceRow, groups []Group) error {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "# %s\n\n", title)
+	buf.WriteString("| Key | Env | Flag | Type | Default | Validation | Secret | Description |\n")
+	buf.WriteString("| --- | --- | --- | --- | --- | --- | --- | --- |\n")
+
+	for _, row := range rows {
+		fmt.Fprintf(&buf, "| %s | %s | %s | %s | %s | %s | %s | %s |\n",
+			markdownCode(row.Key),
+			markdownCode(row.Env),
+			markdownCode(row.Flag),
+			markdownCode(row.Type),
+			markdownCode(row.Default),
+			markdownCode(row.Validation),
+			yesNo(row.Secret),
+			markdownEscape(row.Description),
+		)
+	}
+
+	if len(groups) > 0 {
+		buf.WriteString("\n## Groups\n\n")
+
+		for _, g := range groups {
+			fmt.Fprintf(&buf, "- %s\n", markdownEscape(g.String()))
+		}
+	}
+
+	_, err := w.Write(buf.Bytes())
+
+	return err
+}
+
+// writeSchemaReference writes rows as aligned plain text columns, one key per line, defaults quoted and - standing for
+// keys without env var or flag, followed by groups, one per line.
+func writeSchemaReference(w io.Writer, rows []referenceRow, groups []Group) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "KEY\tENV\tFLAG\tTYPE\tDEFAULT")
+
+	for _, row := range rows {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", row.Key, orDash(row.Env), orDash(row.Flag), row.Type,
+			strconv.Quote(row.Default))
+	}
+
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if len(groups) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+
+	buf.WriteString("\nGROUPS\n")
+
+	for _, g := range groups {
+		fmt.Fprintln(&buf, g.String())
+	}
+
+	_, err := w.Write(buf.Bytes())
+
+	return err
+}
+
+// orDash returns s, or - when empty.
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+
+	return s
+}
+
+// markdownCode formats s as inline code inside a table cell, leaving empty cells empty.
+func markdownCode(s string) string {
+	if s == "" {
+		return ""
+	}
+
+	return "`" + markdownEscape(s) + "`"
+}
+
+// markdownEscape escapes the characters that would break a Markdown table cell.
+func markdownEscape(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+}
+
+// yesNo renders a boolean table cell.
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+
+	return "no"
+}
+
+// htmlReference renders the reference rows as an HTML table.
+var htmlReference = template.Must(template.New("reference").Funcs(template.FuncMap{"yesNo": yesNo}).Parse(`<h1>{{.Title}}</h1>
+<table>
+  <thead>
+    <tr><th>Key</th><th>Env</th><th>Flag</th><th>Type</th><th>Default</th><th>Validation</th><th>Secret</th><th>Description</th></tr>
+  </thead>
+  <tbody>
+{{- range .Rows}}
+    <tr><td><code>{{.Key}}</code></td><td>{{with .Env}}<code>{{.}}</code>{{end}}</td><td>{{with .Flag}}<code>{{.}}</code>{{end}}</td><td><code>{{.Type}}</code></td><td>{{with .Default}}<code>{{.}}</code>{{end}}</td><td>{{with .Validation}}<code>{{.}}</code>{{end}}</td><td>{{yesNo .Secret}}</td><td>{{.Description}}</td></tr>
+{{- end}}
+  </tbody>
+</table>
+{{- with .Groups}}
+<h2>Groups</h2>
+<ul>
+{{- range .}}
+  <li>{{.}}</li>
+{{- end}}
+</ul>
+{{- end}}
+`))
@@ -0,0 +1,146 @@
+package snout_test
+
+import (
+	"bytes"
+
+	"github.com/chiguirez/snout/v3"
+)
+
+type referenceConfig struct {
+	Kafka struct {
+		BrokerAddress string `snout:"broker_address" validate:"required,hostname_port" desc:"Kafka bootstrap broker"`
+		Retries       int    `snout:"retries" default:"3" desc:"Publish retries | 0 disables"`
+	} `snout:"kafka"`
+	Password string `snout:"password" default:"hunter2" secret:"true"`
+}
+
+func (s *snoutSuite) TestMarkdownReference() {
+	s.Run("Given a config Struct with desc, validate and secret tags", func() {
+		kernel := snout.Kernel[referenceConfig]{}
+
+		s.Run("When a Markdown reference is written", func() {
+			var buf bytes.Buffer
+
+			err := kernel.WriteReference(&buf, snout.FormatMarkdown,
+				snout.WithServiceName("orders"),
+				snout.WithEnvVarPrefix("APP"),
+			)
+			s.Require().NoError(err)
+
+			s.Run("Then every key is documented", func() {
+				s.Require().Equal("# orders configuration\n\n"+
+					"| Key | Env | Flag | Type | Default | Validation | Secret | Description |\n"+
+					"| --- | --- | --- | --- | --- | --- | --- | --- |\n"+
+					"| `kafka.broker_address` | `APP_KAFKA_BROKER_ADDRESS` | `--kafka.broker_address` | `string` |  | "+
+					"`required,hostname_port` | no | Kafka bootstrap broker |\n"+
+					"| `kafka.retries` | `APP_KAFKA_RETRIES` | `--kafka.retries` | `int` | `3` |  | no | "+
+					"Publish retries \\| 0 disables |\n"+
+					"| `password` | `APP_PASSWORD` | `--password` | `string` |  |  | yes |  |\n",
+					buf.String())
+			})
+		})
+	})
+}
+
+func (s *snoutSuite) TestHTMLReference() {
+	s.Run("Given a config Struct with desc, validate and secret tags", func() {
+		kernel := snout.Kernel[referenceConfig]{}
+
+		s.Run("When an HTML reference is written", func() {
+			var buf bytes.Buffer
+
+			err := kernel.WriteReference(&buf, snout.FormatHTML, snout.WithEnvVarPrefix("APP"))
+			s.Require().NoError(err)
+
+			s.Run("Then every key is documented and escaped", func() {
+				s.Require().Contains(buf.String(), "<h1>Configuration</h1>")
+				s.Require().Contains(buf.String(), "<td><code>APP_KAFKA_BROKER_ADDRESS</code></td>")
+				s.Require().Contains(buf.String(), "<td>Kafka bootstrap broker</td>")
+				s.Require().Contains(buf.String(), "<td><code>--password</code></td>")
+				s.Require().NotContains(buf.String(), "hunter2")
+			})
+		})
+	})
+}
+
+func (s *snoutSuite) TestUnknownReferenceFormat() {
+	s.Run("Given a config Struct", func() {
+		kernel := snout.Kernel[referenceConfig]{}
+
+		s.Run("When a reference is written in a config format", func() {
+			err := kernel.WriteReference(&bytes.Buffer{}, snout.FormatYAML)
+
+			s.Run("Then an unknown format error is returned", func() {
+				s.Require().ErrorIs(err, snout.ErrUnknownFormat)
+			})
+		})
+	})
+}
+
+func (s *snoutSuite) TestSchemaReference() {
+	s.Run("Given a config Struct with nested keys, defaults and a secret default", func() {
+		kernel := snout.Kernel[referenceConfig]{}
+
+		s.Run("When its schema is written", func() {
+			var buf bytes.Buffer
+
+			err := kernel.WriteReference(&buf, snout.FormatSchema, snout.WithEnvVarPrefix("APP"))
+			s.Require().NoError(err)
+
+			s.Run("Then every key is listed with its env var, flag, type and default, secrets left blank", func() {
+				s.Require().Equal(""+
+					"KEY                   ENV                       FLAG                    TYPE    DEFAULT\n"+
+					"kafka.broker_address  APP_KAFKA_BROKER_ADDRESS  --kafka.broker_address  string  \"\"\n"+
+					"kafka.retries         APP_KAFKA_RETRIES         --kafka.retries         int     \"3\"\n"+
+					"password              APP_PASSWORD              --password              string  \"\"\n",
+					buf.String())
+			})
+		})
+	})
+}
+
+func (s *snoutSuite) TestCollectionReference() {
+	s.Run("Given a config Struct with lists, maps and variants", func() {
+		kernel := snout.Kernel[dumpCollectionConfig]{}
+		opts := []snout.Options{snout.WithEnvVarPrefix("APP"), snout.WithVariant[queue]("sqs", &dumpQueue{})}
+
+		s.Run("When its schema is written", func() {
+			var buf bytes.Buffer
+
+			err := kernel.WriteReference(&buf, snout.FormatSchema, opts...)
+
+			s.Run("Then the keys within elements follow each collection", func() {
+				s.Require().NoError(err)
+				s.Require().Equal(`KEY                                 ENV                                     FLAG                              TYPE                                DEFAULT
+upstreams                           APP_UPSTREAMS                           --upstreams                       []snout_test.dumpUpstream           ""
+upstreams[].host                    APP_UPSTREAMS_<N>_HOST                  --upstreams.host                  string                              ""
+upstreams[].credentials.user        APP_UPSTREAMS_<N>_CREDENTIALS_USER      --upstreams.credentials.user      string                              ""
+upstreams[].credentials.password    APP_UPSTREAMS_<N>_CREDENTIALS_PASSWORD  --upstreams.credentials.password  string                              ""
+tenants                             APP_TENANTS                             --tenants                         map[string]snout_test.dumpUpstream  ""
+tenants.<key>.host                  APP_TENANTS_<KEY>_HOST                  -                                 string                              ""
+tenants.<key>.credentials.user      APP_TENANTS_<KEY>_CREDENTIALS_USER      -                                 string                              ""
+tenants.<key>.credentials.password  APP_TENANTS_<KEY>_CREDENTIALS_PASSWORD  -                                 string                              ""
+queue                               APP_QUEUE                               --queue                           snout_test.queue                    ""
+queue.type                          -                                       -                                 string                              ""
+queue[sqs].url                      -                                       -                                 string                              ""
+queue[sqs].token                    -                                       -                                 string                              ""
+`, buf.String())
+			})
+		})
+
+		s.Run("When its Markdown reference is written", func() {
+			var buf bytes.Buffer
+
+			err := kernel.WriteReference(&buf, snout.FormatMarkdown, opts...)
+
+			s.Run("Then secrets within elements and variants are flagged", func() {
+				s.Require().NoError(err)
+				s.Require().Contains(buf.String(), "| `queue.type` |  |  | `string` |  | `oneof=sqs` | no |  |\n")
+				s.Require().Contains(buf.String(), "| `queue[sqs].token` |  |  | `string` |  |  | yes |  |\n")
+				s.Require().Contains(buf.String(),
+					"| `upstreams[].credentials.password` | `APP_UPSTREAMS_<N>_CREDENTIALS_PASSWORD` | "+
+						"`--upstreams.credentials.password` | `string` |  |  | yes |  |\n")
+			})
+		})
+	})
+}
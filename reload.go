@@ -0,0 +1,78 @@
+package snout
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// watchSources watches the config file and every source able to, reloading the configuration whenever one of them
+// reports a change, until ctx is done.
+func (kb KernelBootstrap[T]) watchSources(ctx context.Context) {
+	changes := make(chan struct{}, 1)
+	notify := func() {
+		select {
+		case changes <- struct{}{}:
+		default:
+		}
+	}
+
+	go func() {
+		if err := watchConfigFile(ctx, kb.options, notify); err != nil && ctx.Err() == nil {
+			logger.Error("Watching config file", slog.Any("error", err))
+		}
+	}()
+
+	for _, source := range kb.options.Sources {
+		watcher, ok := source.(Watcher)
+		if !ok {
+			continue
+		}
+
+		go func(watcher Watcher) {
+			if err := watcher.Watch(ctx, notify); err != nil && ctx.Err() == nil {
+				logger.Error("Watching config source",
+					slog.String("source", fmt.Sprintf("%T", watcher)),
+					slog.Any("error", err),
+				)
+			}
+		}(watcher)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-changes:
+			kb.reloadConfig(ctx)
+		}
+	}
+}
+
+// reloadConfig loads and validates the configuration again, updating the features of the running configuration and
+// handing the reloaded one, sharing those features, to the reload function when valid. An invalid configuration is
+// logged and the kernel keeps running with the previous one.
+func (kb KernelBootstrap[T]) reloadConfig(ctx context.Context) {
+	cfg, err := kb.reload(ctx)
+	if err == nil {
+		err = validateConfig(&cfg, kb.options)
+	}
+
+	if err != nil {
+		logger.Error("Reloading config", slog.Any("error", err))
+
+		return
+	}
+
+	logger.Info("Config reloaded")
+
+	updateFeatures(kb.cfg, &cfg, fieldsOf(&kb.cfg, namingOf(kb.options)))
+
+	if kb.onReload == nil {
+		return
+	}
+
+	if err := kb.onReload(ctx, cfg); err != nil {
+		logger.Error("Applying reloaded config", slog.Any("error", err))
+	}
+}
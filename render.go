@@ -0,0 +1,280 @@
+package snout
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// document renders configuration fields as an env, YAML or JSON document, taking the value and the optional comment
+// of every field from the given functions.
+type document struct {
+	fields  []configField
+	value   func(configField) any
+	comment func(configField) string
+}
+
+// write renders the document in the given format, naming env vars as configured by env.
+func (d document) write(w io.Writer, format Format, env Env) error {
+	switch format {
+	case FormatEnv:
+		return d.writeEnv(w, env)
+	case FormatYAML:
+		return d.writeYAML(w)
+	case FormatJSON:
+		return d.writeJSON(w)
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+}
+
+// fieldComment returns the comment of a field, if the document has any.
+func (d document) fieldComment(f configField) string {
+	if d.comment == nil {
+		return ""
+	}
+
+	return d.comment(f)
+}
+
+// writeEnv writes one KEY=value line per field bound to an env var, preceded by its comment.
+func (d document) writeEnv(w io.Writer, env Env) error {
+	var buf bytes.Buffer
+
+	for _, f := range d.fields {
+		if f.envName(env) == "" {
+			continue
+		}
+
+		if comment := d.fieldComment(f); comment != "" {
+			if buf.Len() > 0 {
+				buf.WriteString("\n")
+			}
+
+			fmt.Fprintf(&buf, "# %s\n", strings.ReplaceAll(comment, "\n", "\n# "))
+		}
+
+		value, err := envValue(f, d.value(f))
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(&buf, "%s=%s\n", f.envName(env), value)
+	}
+
+	_, err := w.Write(buf.Bytes())
+
+	return err
+}
+
+// writeYAML writes a YAML document with the comment of every field above its key.
+func (d document) writeYAML(w io.Writer) error {
+	root := &yaml.Node{Kind: yaml.MappingNode}
+
+	for _, f := range d.fields {
+		mapping := root
+		segments := strings.Split(f.key(), ".")
+
+		for _, segment := range segments[:len(segments)-1] {
+			mapping = yamlChild(mapping, segment)
+		}
+
+		value, err := yamlValue(d.value(f))
+		if err != nil {
+			return err
+		}
+
+		mapping.Content = append(mapping.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: segments[len(segments)-1], HeadComment: d.fieldComment(f)},
+			value,
+		)
+	}
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(&yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{root}}); err != nil {
+		return err
+	}
+
+	return encoder.Close()
+}
+
+// writeJSON writes an indented JSON document keeping the declaration order of the fields.
+func (d document) writeJSON(w io.Writer) error {
+	root := &orderedMap{}
+
+	for _, f := range d.fields {
+		mapping := root
+		segments := strings.Split(f.key(), ".")
+
+		for _, segment := range segments[:len(segments)-1] {
+			mapping = mapping.child(segment)
+		}
+
+		mapping.set(segments[len(segments)-1], d.value(f))
+	}
+
+	out, err := json.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(append(out, '\n'))
+
+	return err
+}
+
+// envValue formats the value of a field the way it is written in its env var: collections and lists as JSON, as
+// snout reads them back, values already given as text as they are, and anything else by envString.
+func envValue(f configField, value any) (string, error) {
+	if _, ok := value.(string); ok || !f.collection() && !isList(value) {
+		return envString(value), nil
+	}
+
+	out, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	return string(out), nil
+}
+
+// isList reports whether value is a list, byte slices aside.
+func isList(value any) bool {
+	rv := reflect.ValueOf(value)
+
+	return (rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array) && rv.Type().Elem().Kind() != reflect.Uint8
+}
+
+// envString formats a value the way it is written in an env var, joining lists with commas.
+func envString(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case time.Duration:
+		return v.String()
+	}
+
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		items := make([]string, 0, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			items = append(items, envString(rv.Index(i).Interface()))
+		}
+
+		return strings.Join(items, ",")
+	}
+
+	return fmt.Sprint(value)
+}
+
+// yamlChild returns the mapping stored under key in mapping, appending an empty one when missing.
+func yamlChild(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+
+	child := &yaml.Node{Kind: yaml.MappingNode}
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, child)
+
+	return child
+}
+
+// yamlValue encodes v as a YAML node.
+func yamlValue(v any) (*yaml.Node, error) {
+	var node yaml.Node
+	if err := node.Encode(v); err != nil {
+		return nil, err
+	}
+
+	return &node, nil
+}
+
+// orderedMap is a JSON object that keeps its keys in insertion order.
+type orderedMap struct {
+	keys   []string
+	values map[string]any
+}
+
+// set stores value under key, keeping the position of an existing key.
+func (m *orderedMap) set(key string, value any) {
+	if m.values == nil {
+		m.values = map[string]any{}
+	}
+
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+
+	m.values[key] = value
+}
+
+// child returns the object stored under key, creating it when missing.
+func (m *orderedMap) child(key string) *orderedMap {
+	if child, ok := m.values[key].(*orderedMap); ok {
+		return child
+	}
+
+	child := &orderedMap{}
+	m.set(key, child)
+
+	return child
+}
+
+// MarshalYAML implements yaml.Marshaler, keeping the keys in insertion order.
+func (m *orderedMap) MarshalYAML() (any, error) {
+	mapping := &yaml.Node{Kind: yaml.MappingNode}
+
+	for _, key := range m.keys {
+		value, err := yamlValue(m.values[key])
+		if err != nil {
+			return nil, err
+		}
+
+		mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+	}
+
+	return mapping, nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (m *orderedMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.WriteByte('{')
+
+	for i, key := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+
+		v, err := json.Marshal(m.values[key])
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
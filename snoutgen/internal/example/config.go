@@ -0,0 +1,46 @@
+// Package example holds a configuration type with a generated loader, checked against the reflective load by the
+// snoutgen tests.
+package example
+
+import (
+	"time"
+
+	"github.com/chiguirez/snout/v3"
+)
+
+//go:generate go run github.com/chiguirez/snout/v3/cmd/snoutgen -type Config
+
+// Level is a named string type.
+type Level string
+
+// Config is a configuration covering every kind of key snoutgen supports.
+type Config struct {
+	Common
+	Name    string        `snout:"name" validate:"required"`
+	Debug   bool          `snout:"debug"`
+	Level   Level         `snout:"level" default:"info" validate:"oneof=debug info warn"`
+	Port    uint16        `snout:"port" default:"8080" validate:"gte=1024"`
+	Ratio   float32       `snout:"ratio" default:"0.5"`
+	Timeout time.Duration `snout:"timeout" default:"5s" validate:"gte=1s"`
+	Tags    []string      `snout:"tags"`
+	Retries []int         `snout:"retries"`
+	Beta    snout.Feature `snout:"beta" default:"25%"`
+	Audit   *bool         `snout:"audit" validate:"required"`
+	Kafka   struct {
+		Brokers []string `snout:"brokers" validate:"min=1"`
+		Topic   string   `snout:"topic" default:"events"`
+	} `snout:"kafka"`
+	DB *Database `snout:"db"`
+}
+
+// Common holds keys shared by several configurations, embedded and flattened into their keys.
+type Common struct {
+	Region string `snout:"region" default:"eu-west-1" validate:"required"`
+}
+
+// Database is a section behind a pointer, allocated when any of its keys is set.
+type Database struct {
+	DSN      string  `snout:"dsn" validate:"required,url"`
+	PoolSize *int    `snout:"pool_size" validate:"omitempty,max=100"`
+	Schema   *string `snout:"schema"`
+}
@@ -0,0 +1,486 @@
+// Package snoutgen generates snout loaders, implementing snout.Loader for a configuration type so that the kernel
+// loads it without reflection. It backs the snoutgen command, usually run through go generate next to the type:
+//
+//	//go:generate go run github.com/chiguirez/snout/v3/cmd/snoutgen -type Config
+//
+// Generation fails on fields snout can't load: exported fields without snout tag, keys declared twice, defaults
+// that don't decode into their field and types other than strings, booleans, numbers, durations, features, slices
+// of those and pointers to any of them.
+package snoutgen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/types"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/chiguirez/snout/v3"
+)
+
+// snoutPath is the import path of package snout.
+const snoutPath = "github.com/chiguirez/snout/v3"
+
+// loadMode type checks the package and its dependencies from source, which doesn't depend on the export data format
+// of the toolchain at hand.
+const loadMode = packages.NeedName | packages.NeedTypes | packages.NeedImports | packages.NeedDeps | packages.NeedSyntax
+
+// ErrGenerate is an error indicating a loader could not be generated for a type.
+var ErrGenerate = errors.New("snoutgen")
+
+// FileName returns the name of the file the loader of typeName is written to, e.g. config_snout.go for Config.
+func FileName(typeName string) string {
+	return strings.ToLower(typeName) + "_snout.go"
+}
+
+// Generate returns the source of the loader of the struct type typeName declared in the package in dir.
+func Generate(dir, typeName string) ([]byte, error) {
+	pkgs, err := packages.Load(&packages.Config{Mode: loadMode, Dir: dir}, ".")
+	if err != nil {
+		return nil, fmt.Errorf("%w: loading %s: %w", ErrGenerate, dir, err)
+	}
+
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("%w: loading %s: found %d packages, expecting one", ErrGenerate, dir, len(pkgs))
+	}
+
+	if len(pkgs[0].Errors) > 0 {
+		return nil, fmt.Errorf("%w: loading %s: %v", ErrGenerate, dir, pkgs[0].Errors)
+	}
+
+	pkg := pkgs[0].Types
+
+	obj, ok := pkg.Scope().Lookup(typeName).(*types.TypeName)
+	if !ok {
+		return nil, fmt.Errorf("%w: type %s not found in %s", ErrGenerate, typeName, pkg.Path())
+	}
+
+	s, ok := obj.Type().Underlying().(*types.Struct)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s is not a struct", ErrGenerate, typeName)
+	}
+
+	g := &generator{pkg: pkg, imports: map[string]string{"fmt": "fmt", "reflect": "reflect", snoutPath: "snout"}}
+	if err := g.walk(s, "", "c", nil, nil); err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrGenerate, typeName, err)
+	}
+
+	return g.render(typeName)
+}
+
+// leaf is a configuration key of the type a loader is generated for.
+type leaf struct {
+	key     string
+	field   string
+	index   []int
+	tag     reflect.StructTag
+	typ     types.Type
+	expr    string
+	parents []parentField
+}
+
+// generator collects the keys of a configuration type and renders its loader.
+type generator struct {
+	pkg     *types.Package
+	imports map[string]string
+	leaves  []leaf
+	keys    map[string]string
+}
+
+// walk collects the leaves of s, whose key is path and whose value is the Go expression expr. parents lists the
+// expressions of the pointers to structs on the way, which must be allocated before setting a leaf.
+func (g *generator) walk(s *types.Struct, path, expr string, index []int, parents []parentField) error {
+	if g.keys == nil {
+		g.keys = map[string]string{}
+	}
+
+	for i := 0; i < s.NumFields(); i++ {
+		field := s.Field(i)
+		if !field.Exported() {
+			continue
+		}
+
+		tag := reflect.StructTag(s.Tag(i))
+		name := tag.Get("snout")
+		nested, isNested := nestedStruct(field.Type())
+
+		// Embedded structs without snout tag are flattened into the keys of s.
+		if name == "" && !(field.Embedded() && isNested) {
+			return fmt.Errorf("field %s has no snout tag", field.Name())
+		}
+
+		key := name
+		switch {
+		case name == "":
+			key = path
+		case path != "":
+			key = path + "." + name
+		}
+
+		fieldExpr := expr + "." + field.Name()
+		fieldIndex := append(append([]int{}, index...), i)
+
+		if isNested {
+			fieldParents := parents
+			if p, ok := field.Type().Underlying().(*types.Pointer); ok {
+				fieldParents = append(append([]parentField{}, parents...), parentField{expr: fieldExpr, typ: p.Elem()})
+			}
+
+			if err := g.walk(nested, key, fieldExpr, fieldIndex, fieldParents); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if other, ok := g.keys[key]; ok {
+			return fmt.Errorf("duplicate key %s, declared by fields %s and %s", key, other, field.Name())
+		}
+
+		g.keys[key] = field.Name()
+
+		if _, err := g.decoder(field.Type(), "raw", "v", ""); err != nil {
+			return fmt.Errorf("field %s: %w", field.Name(), err)
+		}
+
+		if value := tag.Get("default"); value != "" {
+			if err := checkDefault(value, field.Type()); err != nil {
+				return fmt.Errorf("field %s: default %q: %w", field.Name(), value, err)
+			}
+		}
+
+		g.leaves = append(g.leaves, leaf{
+			key:     key,
+			field:   field.Name(),
+			index:   fieldIndex,
+			tag:     tag,
+			typ:     field.Type(),
+			expr:    fieldExpr,
+			parents: parents,
+		})
+	}
+
+	return nil
+}
+
+// decoder returns the statements decoding the config value held by the variable raw into a new variable named
+// after out, of type t, running the statement fail on errors.
+func (g *generator) decoder(t types.Type, raw, out, fail string) (string, error) {
+	typeName := g.typeString(t)
+
+	switch {
+	case isNamed(t, "time", "Duration"):
+		return decode(fmt.Sprintf("snout.DecodeDuration(%s)", raw), "time.Duration", typeName, out, fail), nil
+	case isNamed(t, snoutPath, "Feature"):
+		return decode(fmt.Sprintf("snout.DecodeFeature(%s)", raw), "snout.Feature", typeName, out, fail), nil
+	}
+
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		info := u.Info()
+
+		switch {
+		case info&types.IsString != 0:
+			return decode(fmt.Sprintf("snout.DecodeString(%s)", raw), "string", typeName, out, fail), nil
+		case info&types.IsBoolean != 0:
+			return decode(fmt.Sprintf("snout.DecodeBool(%s)", raw), "bool", typeName, out, fail), nil
+		case info&types.IsUnsigned != 0:
+			return decode(fmt.Sprintf("snout.DecodeUint(%s, %d)", raw, bitSize(u)), "uint64", typeName, out, fail), nil
+		case info&types.IsInteger != 0:
+			return decode(fmt.Sprintf("snout.DecodeInt(%s, %d)", raw, bitSize(u)), "int64", typeName, out, fail), nil
+		case info&types.IsFloat != 0:
+			return decode(fmt.Sprintf("snout.DecodeFloat(%s, %d)", raw, bitSize(u)), "float64", typeName, out, fail), nil
+		}
+	case *types.Pointer:
+		elem, err := g.decoder(u.Elem(), raw, out+"Value", fail)
+		if err != nil {
+			return "", err
+		}
+
+		return fmt.Sprintf("%s%s := &%sValue\n", elem, out, out), nil
+	case *types.Slice:
+		elem, err := g.decoder(u.Elem(), "item", "elem", fail)
+		if err != nil {
+			return "", err
+		}
+
+		return fmt.Sprintf("items, err := snout.DecodeList(%s)\nif err != nil {\n%s\n}\n"+
+			"%s := make(%s, 0, len(items))\nfor _, item := range items {\n%s%s = append(%s, elem)\n}\n",
+			raw, fail, out, typeName, elem, out, out), nil
+	}
+
+	return "", fmt.Errorf("unsupported type %s", typeName)
+}
+
+// decode returns the statements assigning the result of call, of type decoded, to a new variable named after out of
+// type typeName, running the statement fail on errors.
+func decode(call, decoded, typeName, out, fail string) string {
+	if typeName == decoded {
+		return fmt.Sprintf("%s, err := %s\nif err != nil {\n%s\n}\n", out, call, fail)
+	}
+
+	return fmt.Sprintf("decoded, err := %s\nif err != nil {\n%s\n}\n%s := %s(decoded)\n", call, fail, out, typeName)
+}
+
+// typeString returns the Go expression of t within the generated file, recording the imports it needs.
+func (g *generator) typeString(t types.Type) string {
+	return types.TypeString(t, func(pkg *types.Package) string {
+		if pkg == g.pkg {
+			return ""
+		}
+
+		g.imports[pkg.Path()] = pkg.Name()
+
+		return pkg.Name()
+	})
+}
+
+// validated returns the expression of the value of l handed to snout.ValidateValue: its underlying basic type
+// when it is named, so that it is checked natively.
+func (g *generator) validated(l leaf, expr string) string {
+	t := l.typ
+	if p, ok := t.Underlying().(*types.Pointer); ok {
+		t = p.Elem()
+	}
+
+	if _, named := t.(*types.Named); named && !isNamed(t, "time", "Duration") {
+		if basic, ok := t.Underlying().(*types.Basic); ok {
+			return basic.Name() + "(" + expr + ")"
+		}
+	}
+
+	return expr
+}
+
+// pointerRules returns rules without the required and omitempty rules of a pointer, which the validator decides from
+// the pointer being set, keeping those following a dive as they apply to the elements.
+func pointerRules(rules string) string {
+	own, dive, hasDive := strings.Cut(rules, "dive")
+
+	var kept []string
+
+	for _, rule := range strings.Split(own, ",") {
+		if rule != "" && rule != "required" && rule != "omitempty" {
+			kept = append(kept, rule)
+		}
+	}
+
+	if hasDive {
+		kept = append(kept, "dive"+dive)
+	}
+
+	return strings.Join(kept, ",")
+}
+
+// render writes the loader of typeName.
+func (g *generator) render(typeName string) ([]byte, error) {
+	var body bytes.Buffer
+
+	fmt.Fprintf(&body, "\n// SnoutKeys implements snout.Loader.\nfunc (c *%s) SnoutKeys() []snout.Key {\n", typeName)
+	body.WriteString("return []snout.Key{\n")
+
+	for _, l := range g.leaves {
+		index := make([]string, 0, len(l.index))
+		for _, i := range l.index {
+			index = append(index, strconv.Itoa(i))
+		}
+
+		fmt.Fprintf(&body, "{Path: %q, Field: %q, Index: []int{%s}, Type: reflect.TypeOf((*%s)(nil)).Elem(), Tag: %s},\n",
+			l.key, l.field, strings.Join(index, ", "), g.typeString(l.typ), quoteTag(l.tag))
+	}
+
+	body.WriteString("}\n}\n")
+
+	fmt.Fprintf(&body, "\n// SnoutDecode implements snout.Loader.\nfunc (c *%s) SnoutDecode(get func(key string) any) error {\n",
+		typeName)
+
+	for _, l := range g.leaves {
+		fail := fmt.Sprintf("return fmt.Errorf(%q, err)", l.key+": %w")
+		decoder, _ := g.decoder(l.typ, "raw", "v", fail)
+
+		fmt.Fprintf(&body, "if raw := get(%q); raw != nil {\n%s", l.key, decoder)
+
+		for _, parent := range l.parents {
+			fmt.Fprintf(&body, "if %[1]s == nil {\n%[1]s = new(%[2]s)\n}\n", parent.expr, g.typeString(parent.typ))
+		}
+
+		fmt.Fprintf(&body, "%s = v\n}\n\n", l.expr)
+	}
+
+	body.WriteString("return nil\n}\n")
+
+	fmt.Fprintf(&body, "\n// SnoutValidate implements snout.Loader.\nfunc (c *%s) SnoutValidate() snout.ValidationErrors {\n",
+		typeName)
+	body.WriteString("var errs snout.ValidationErrors\n\n")
+
+	for _, l := range g.leaves {
+		rules := l.tag.Get("validate")
+		if rules == "" {
+			continue
+		}
+
+		var conditions []string
+		for _, parent := range l.parents {
+			conditions = append(conditions, parent.expr+" != nil")
+		}
+
+		if len(conditions) > 0 {
+			fmt.Fprintf(&body, "if %s {\n", strings.Join(conditions, " && "))
+		}
+
+		if _, ok := l.typ.Underlying().(*types.Pointer); !ok {
+			fmt.Fprintf(&body, "errs = append(errs, snout.ValidateValue(%q, %s, %q)...)\n", l.key, g.validated(l, l.expr), rules)
+		} else if valueRules := pointerRules(rules); valueRules != "" {
+			// required and omitempty are decided by the pointer being set, the other rules check the value it points to
+			fmt.Fprintf(&body, "if %[1]s != nil {\nerrs = append(errs, snout.ValidateValue(%[2]q, %[3]s, %[4]q)...)\n"+
+				"} else {\nerrs = append(errs, snout.ValidateValue(%[2]q, nil, %[5]q)...)\n}\n",
+				l.expr, l.key, g.validated(l, "*"+l.expr), valueRules, rules)
+		} else {
+			fmt.Fprintf(&body, "if %s == nil {\nerrs = append(errs, snout.ValidateValue(%q, nil, %q)...)\n}\n",
+				l.expr, l.key, rules)
+		}
+
+		if len(conditions) > 0 {
+			body.WriteString("}\n")
+		}
+	}
+
+	body.WriteString("\nreturn errs\n}\n")
+
+	var src bytes.Buffer
+
+	src.WriteString("// Code generated by snoutgen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&src, "package %s\n\nimport (\n", g.pkg.Name())
+
+	paths := make([]string, 0, len(g.imports))
+	for path := range g.imports {
+		paths = append(paths, path)
+	}
+
+	sort.Strings(paths)
+
+	for _, std := range []bool{true, false} {
+		for _, path := range paths {
+			if isStd(path) == std {
+				fmt.Fprintf(&src, "%q\n", path)
+			}
+		}
+
+		src.WriteString("\n")
+	}
+
+	src.WriteString(")\n")
+	src.Write(body.Bytes())
+
+	out, err := format.Source(src.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("%w: formatting loader: %w", ErrGenerate, err)
+	}
+
+	return out, nil
+}
+
+// parentField is a pointer to struct field on the way to a leaf, allocated before setting it.
+type parentField struct {
+	expr string
+	typ  types.Type
+}
+
+// nestedStruct returns the struct t, or t points to, when it holds further configuration keys as snout walks it.
+func nestedStruct(t types.Type) (*types.Struct, bool) {
+	if p, ok := t.Underlying().(*types.Pointer); ok {
+		t = p.Elem()
+	}
+
+	if isNamed(t, "time", "Time") || isNamed(t, snoutPath, "Feature") {
+		return nil, false
+	}
+
+	s, ok := t.Underlying().(*types.Struct)
+
+	return s, ok
+}
+
+// checkDefault decodes a default tag as snout does at load time.
+func checkDefault(value string, t types.Type) error {
+	if p, ok := t.Underlying().(*types.Pointer); ok {
+		t = p.Elem()
+	}
+
+	var err error
+
+	switch {
+	case isNamed(t, "time", "Duration"):
+		_, err = snout.DecodeDuration(value)
+	case isNamed(t, snoutPath, "Feature"):
+		_, err = snout.DecodeFeature(value)
+	default:
+		switch u := t.Underlying().(type) {
+		case *types.Basic:
+			info := u.Info()
+
+			switch {
+			case info&types.IsBoolean != 0:
+				_, err = snout.DecodeBool(value)
+			case info&types.IsUnsigned != 0:
+				_, err = snout.DecodeUint(value, bitSize(u))
+			case info&types.IsInteger != 0:
+				_, err = snout.DecodeInt(value, bitSize(u))
+			case info&types.IsFloat != 0:
+				_, err = snout.DecodeFloat(value, bitSize(u))
+			}
+		case *types.Slice:
+			err = checkDefault(value, u.Elem())
+		}
+	}
+
+	return err
+}
+
+// bitSize returns the size in bits of the basic numeric type t.
+func bitSize(t *types.Basic) int {
+	switch t.Kind() {
+	case types.Int8, types.Uint8:
+		return 8
+	case types.Int16, types.Uint16:
+		return 16
+	case types.Int32, types.Uint32, types.Float32:
+		return 32
+	default:
+		return 64
+	}
+}
+
+// quoteTag returns the Go literal of a struct tag, raw unless it holds a backquote.
+func quoteTag(tag reflect.StructTag) string {
+	if strings.Contains(string(tag), "`") {
+		return strconv.Quote(string(tag))
+	}
+
+	return "`" + string(tag) + "`"
+}
+
+// isStd reports whether path is the import path of a standard library package.
+func isStd(path string) bool {
+	first, _, _ := strings.Cut(path, "/")
+
+	return !strings.Contains(first, ".")
+}
+
+// isNamed reports whether t is the named type pkg.name.
+func isNamed(t types.Type, pkg, name string) bool {
+	named, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+
+	obj := named.Obj()
+
+	return obj.Pkg() != nil && obj.Pkg().Path() == pkg && obj.Name() == name
+}
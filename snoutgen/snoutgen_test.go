@@ -0,0 +1,137 @@
+package snoutgen_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/chiguirez/snout/v3"
+	"github.com/chiguirez/snout/v3/snoutgen"
+	"github.com/chiguirez/snout/v3/snoutgen/internal/example"
+	"github.com/chiguirez/snout/v3/snouttest"
+)
+
+// reflectiveConfig has the fields of example.Config but none of its generated methods, so it loads through
+// reflection.
+type reflectiveConfig example.Config
+
+type snoutgenSuite struct {
+	suite.Suite
+}
+
+func TestSnoutgen(t *testing.T) {
+	suite.Run(t, new(snoutgenSuite))
+}
+
+func (s *snoutgenSuite) TestGenerate() {
+	s.Run("Given the example config with a checked in loader", func() {
+		dir := filepath.Join("internal", "example")
+
+		s.Run("When its loader is generated again", func() {
+			src, err := snoutgen.Generate(dir, "Config")
+			s.Require().NoError(err)
+
+			s.Run("Then it matches the checked in loader", func() {
+				want, err := os.ReadFile(filepath.Join(dir, snoutgen.FileName("Config")))
+				s.Require().NoError(err)
+				s.Equal(string(want), string(src), "run go generate ./... to update it")
+			})
+		})
+	})
+}
+
+func (s *snoutgenSuite) TestGenerateInvalid() {
+	s.Run("Given config types snout can't load", func() {
+		dir := filepath.Join("testdata", "invalid")
+
+		for typeName, msg := range map[string]string{
+			"NoTag":           "field Name has no snout tag",
+			"Duplicate":       "duplicate key name, declared by fields Name and Alias",
+			"Unsupported":     "field Labels: unsupported type map[string]string",
+			"UnsupportedTime": "field Since: unsupported type time.Time",
+			"BadDefault":      `field Timeout: default "soon"`,
+			"NotStruct":       "NotStruct is not a struct",
+			"Missing":         "type Missing not found",
+		} {
+			s.Run("When a loader is generated for "+typeName, func() {
+				_, err := snoutgen.Generate(dir, typeName)
+
+				s.Run("Then generation fails", func() {
+					s.ErrorIs(err, snoutgen.ErrGenerate)
+					s.ErrorContains(err, msg)
+				})
+			})
+		}
+	})
+}
+
+func (s *snoutgenSuite) TestLoader() {
+	s.Run("Given the example config loaded with its generated loader and through reflection", func() {
+		opts := []snout.Options{
+			snouttest.WithConfig(map[string]any{"kafka.brokers": []any{"a:9092", "b:9092"}, "db.dsn": "postgres://db"}),
+			snouttest.WithEnv(map[string]string{"APP_NAME": "orders", "APP_PORT": "9090", "APP_DB_POOL_SIZE": "20"}),
+			snout.WithEnvVarPrefix("APP"),
+			snout.WithArgs("--debug", "--retries=1,2,3", "--timeout=1m", "--beta=50%", "--audit=false"),
+		}
+
+		generated := snouttest.Start(s.T(), snout.Kernel[example.Config]{RunE: waitDone[example.Config]}, opts...)
+		reflective := snouttest.Start(s.T(), snout.Kernel[reflectiveConfig]{RunE: waitDone[reflectiveConfig]}, opts...)
+
+		s.Run("When both kernels become ready", func() {
+			generated.WaitReady()
+			reflective.WaitReady()
+
+			s.Run("Then they load the same configuration", func() {
+				cfg := generated.Config()
+				s.Equal(example.Config(reflective.Config()), cfg)
+				s.Equal("orders", cfg.Name)
+				s.True(cfg.Debug)
+				s.Equal(example.Level("info"), cfg.Level)
+				s.Equal(uint16(9090), cfg.Port)
+				s.Equal(float32(0.5), cfg.Ratio)
+				s.Equal(time.Minute, cfg.Timeout)
+				s.Equal([]int{1, 2, 3}, cfg.Retries)
+				s.Equal(50.0, cfg.Beta.Percentage())
+				s.Require().NotNil(cfg.Audit)
+				s.False(*cfg.Audit)
+				s.Equal([]string{"a:9092", "b:9092"}, cfg.Kafka.Brokers)
+				s.Equal("events", cfg.Kafka.Topic)
+				s.Require().NotNil(cfg.DB)
+				s.Equal("postgres://db", cfg.DB.DSN)
+				s.Equal(20, *cfg.DB.PoolSize)
+			})
+		})
+	})
+
+	s.Run("Given the example config breaking its validate tags", func() {
+		opts := []snout.Options{
+			snouttest.WithConfig(map[string]any{"level": "trace", "port": 80, "db.dsn": "not a url"}),
+		}
+
+		generated := snouttest.Start(s.T(), snout.Kernel[example.Config]{RunE: waitDone[example.Config]}, opts...)
+		reflective := snouttest.Start(s.T(), snout.Kernel[reflectiveConfig]{RunE: waitDone[reflectiveConfig]}, opts...)
+
+		s.Run("When both kernels are initialized", func() {
+			generatedErr, reflectiveErr := generated.Wait(), reflective.Wait()
+
+			s.Run("Then they report the same validation errors", func() {
+				var generatedErrs, reflectiveErrs snout.ValidationErrors
+				s.Require().ErrorAs(generatedErr, &generatedErrs)
+				s.Require().ErrorAs(reflectiveErr, &reflectiveErrs)
+				s.ElementsMatch(reflectiveErrs, generatedErrs)
+				s.Len(generatedErrs, 5)
+			})
+		})
+	})
+}
+
+func waitDone[T any](ctx context.Context, _ T) error {
+	snout.Ready(ctx)
+	<-ctx.Done()
+
+	return nil
+}
@@ -0,0 +1,26 @@
+package invalid
+
+import "time"
+
+type NoTag struct {
+	Name string
+}
+
+type Duplicate struct {
+	Name  string `snout:"name"`
+	Alias string `snout:"name"`
+}
+
+type Unsupported struct {
+	Labels map[string]string `snout:"labels"`
+}
+
+type UnsupportedTime struct {
+	Since time.Time `snout:"since"`
+}
+
+type BadDefault struct {
+	Timeout time.Duration `snout:"timeout" default:"soon"`
+}
+
+type NotStruct string
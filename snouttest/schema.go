@@ -0,0 +1,125 @@
+package snouttest
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/chiguirez/snout/v3"
+)
+
+// update rewrites golden files instead of comparing against them, as in go test ./... -snouttest.update. It is the
+// fallback for the -update flag, registered at init unless a package initialized earlier already defines it.
+var update = flag.Bool("snouttest.update", false, "update snouttest golden files")
+
+func init() {
+	if flag.Lookup("update") == nil {
+		flag.Bool("update", false, "update golden files")
+	}
+}
+
+// updating reports whether golden files are to be rewritten, as set by -update or -snouttest.update.
+func updating() bool {
+	if *update {
+		return true
+	}
+
+	if f := flag.Lookup("update"); f != nil {
+		set, _ := strconv.ParseBool(f.Value.String())
+
+		return set
+	}
+
+	return false
+}
+
+// AssertConfigSchema compares the schema of T, the key, env var, flag, type and default of every key as written by
+// snout.FormatSchema, against the golden file at goldenPath and fails the test when they differ. Renaming a field or
+// changing a default thus shows up as a test failure to be acknowledged by running the tests with -update, or
+// -snouttest.update, which rewrites the golden file.
+//
+// opts are the options the kernel is bootstrapped with, such as snout.WithEnvVarPrefix, since they change the
+// derived env var names.
+func AssertConfigSchema[T any](t testing.TB, goldenPath string, opts ...snout.Options) {
+	t.Helper()
+
+	var (
+		kernel snout.Kernel[T]
+		got    bytes.Buffer
+	)
+
+	if err := kernel.WriteReference(&got, snout.FormatSchema, opts...); err != nil {
+		t.Fatalf("snouttest: writing config schema: %v", err)
+	}
+
+	if updating() {
+		if err := os.MkdirAll(filepath.Dir(goldenPath), 0o755); err != nil {
+			t.Fatalf("snouttest: updating %s: %v", goldenPath, err)
+		}
+
+		if err := os.WriteFile(goldenPath, got.Bytes(), 0o644); err != nil {
+			t.Fatalf("snouttest: updating %s: %v", goldenPath, err)
+		}
+
+		return
+	}
+
+	want, err := os.ReadFile(goldenPath)
+	if err != nil {
+		t.Fatalf("snouttest: reading golden file, run with -update to create it: %v", err)
+	}
+
+	if diff := diffLines(schemaLines(string(want)), schemaLines(got.String())); diff != "" {
+		t.Errorf("snouttest: config schema differs from %s, run with -update if the change is intended:\n%s",
+			goldenPath, diff)
+	}
+}
+
+// schemaLines splits a schema into lines with their column padding collapsed, so that a key widening a column
+// doesn't show up as a change of every line.
+func schemaLines(schema string) []string {
+	lines := strings.Split(strings.TrimSuffix(schema, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.Join(strings.Fields(line), " ")
+	}
+
+	return lines
+}
+
+// diffLines lists the lines of want missing from got prefixed with -, and the lines of got missing from want
+// prefixed with +. Schema lines are unique, one per key, so a set difference is enough to tell what changed.
+func diffLines(wantLines, gotLines []string) string {
+	var diff strings.Builder
+
+	for _, line := range missing(wantLines, gotLines) {
+		diff.WriteString("- " + line + "\n")
+	}
+
+	for _, line := range missing(gotLines, wantLines) {
+		diff.WriteString("+ " + line + "\n")
+	}
+
+	return diff.String()
+}
+
+// missing returns the lines of a not found in b, in order.
+func missing(a, b []string) []string {
+	present := make(map[string]bool, len(b))
+	for _, line := range b {
+		present[line] = true
+	}
+
+	var lines []string
+
+	for _, line := range a {
+		if !present[line] {
+			lines = append(lines, line)
+		}
+	}
+
+	return lines
+}
@@ -0,0 +1,84 @@
+package snouttest_test
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/chiguirez/snout/v3"
+	"github.com/chiguirez/snout/v3/snouttest"
+)
+
+type renamedConfig struct {
+	Kafka struct {
+		Topic string `snout:"topic_name"`
+		Group string `snout:"group" default:"consumers"`
+	} `snout:"kafka"`
+	Port int    `snout:"port" validate:"required"`
+	Mode string `snout:"mode" default:"serve"`
+}
+
+// recorder is a testing.TB recording the failures reported to it.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (s *snouttestSuite) TestAssertConfigSchema() {
+	s.Run("Given a golden file matching the schema of a config Struct", func() {
+		golden := "testdata/schema.golden"
+
+		s.Run("When the schema is asserted", func() {
+			r := &recorder{TB: s.T()}
+			snouttest.AssertConfigSchema[harnessConfig](r, golden, snout.WithEnvVarPrefix("APP"))
+
+			s.Run("Then the test passes", func() {
+				s.Empty(r.errors)
+			})
+		})
+
+		s.Run("When a key is renamed and a default changed", func() {
+			r := &recorder{TB: s.T()}
+			snouttest.AssertConfigSchema[renamedConfig](r, golden, snout.WithEnvVarPrefix("APP"))
+
+			s.Run("Then the test fails listing the changed lines", func() {
+				s.Require().Len(r.errors, 1)
+				s.Contains(r.errors[0], "- kafka.topic APP_KAFKA_TOPIC --kafka.topic string \"\"\n")
+				s.Contains(r.errors[0], "- kafka.group APP_KAFKA_GROUP --kafka.group string \"workers\"\n")
+				s.Contains(r.errors[0], "+ kafka.topic_name APP_KAFKA_TOPIC_NAME --kafka.topic_name string \"\"\n")
+				s.Contains(r.errors[0], "+ kafka.group APP_KAFKA_GROUP --kafka.group string \"consumers\"\n")
+				s.NotContains(r.errors[0], "port")
+			})
+		})
+	})
+}
+
+func (s *snouttestSuite) TestAssertConfigSchemaUpdate() {
+	s.Run("Given a golden file not written yet", func() {
+		golden := filepath.Join(s.T().TempDir(), "testdata", "schema.golden")
+
+		s.Run("When the schema is asserted with -update set", func() {
+			s.Require().NoError(flag.Set("update", "true"))
+			defer func() { s.Require().NoError(flag.Set("update", "false")) }()
+
+			r := &recorder{TB: s.T()}
+			snouttest.AssertConfigSchema[renamedConfig](r, golden, snout.WithEnvVarPrefix("APP"))
+
+			s.Run("Then the golden file is written", func() {
+				s.Empty(r.errors)
+
+				content, err := os.ReadFile(golden)
+				s.Require().NoError(err)
+				s.Contains(string(content), "kafka.topic_name")
+			})
+		})
+	})
+}
@@ -0,0 +1,236 @@
+// Package snouttest runs snout kernels in-process for tests.
+//
+// A kernel started through Start reads its configuration only from what the test hands it: config values given with
+// WithConfig, env vars given with WithEnv and flags given with snout.WithArgs. Neither the process env, os.Args nor
+// any config file in the working directory leak in, so tests built on it can run with t.Parallel:
+//
+//	h := snouttest.Start(t, kernel,
+//		snouttest.WithConfig(map[string]any{"kafka.topic": "orders"}),
+//		snouttest.WithEnv(map[string]string{"APP_HTTP_PORT": "0"}),
+//		snout.WithEnvVarPrefix("APP"),
+//	)
+//	h.WaitReady()
+//
+//	// exercise the running service
+//
+//	if err := h.Shutdown(); err != nil {
+//		t.Fatal(err)
+//	}
+//
+// AssertConfigSchema compares the config schema against a golden file, rewritten when the tests run with -update. The
+// flag is registered at init unless a package initialized earlier already defines it, so a test package wanting its
+// own -update flag reuses it through flag.Lookup("update") instead of defining it again; -snouttest.update works
+// either way.
+package snouttest
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/chiguirez/snout/v3"
+)
+
+// timeout bounds every wait on the kernel.
+const timeout = 10 * time.Second
+
+// Harness is a kernel running in-process for the duration of a test.
+type Harness[T any] struct {
+	t       testing.TB
+	signals chan os.Signal
+	started chan struct{}
+	ready   chan struct{}
+	done    chan struct{}
+	output  *buffer
+	cfg     T
+	err     error
+}
+
+// Start bootstraps and initializes kernel in a new goroutine and returns its harness.
+//
+// The kernel reads no env var, flag or config file unless given one: opts are applied on top of an empty env, no
+// args and an empty config folder. Reports printed by snout are kept for Output. The kernel is shut down, if still
+// running, when the test ends.
+func Start[T any](t testing.TB, kernel snout.Kernel[T], opts ...snout.Options) *Harness[T] {
+	t.Helper()
+
+	h := &Harness[T]{
+		t:       t,
+		signals: make(chan os.Signal, 1),
+		started: make(chan struct{}),
+		ready:   make(chan struct{}),
+		done:    make(chan struct{}),
+		output:  &buffer{},
+	}
+
+	var once sync.Once
+
+	options := append([]snout.Options{
+		snout.WithArgs(),
+		WithEnv(nil),
+		snout.WithEnvVarFolderLocation(t.TempDir()),
+		snout.WithOutput(h.output),
+	}, opts...)
+	options = append(options,
+		snout.WithSignals(h.signals),
+		snout.WithReadyHook(func() { once.Do(func() { close(h.ready) }) }),
+	)
+
+	runE := kernel.RunE
+	kernel.RunE = func(ctx context.Context, cfg T) error {
+		h.cfg = cfg
+		close(h.started)
+
+		return runE(ctx, cfg)
+	}
+
+	go func() {
+		defer close(h.done)
+
+		h.err = kernel.Bootstrap(context.Background(), options...).Initialize()
+	}()
+
+	t.Cleanup(func() {
+		select {
+		case <-h.done:
+		default:
+			h.Signal(syscall.SIGTERM)
+			<-h.done
+		}
+	})
+
+	return h
+}
+
+// WithConfig registers values as a config source, keyed by snout keys either nested or dotted, as in kafka.topic.
+func WithConfig(values map[string]any) snout.Options {
+	return snout.WithSource(configSource(values))
+}
+
+// WithEnv makes env the only environment variables the kernel sees.
+func WithEnv(env map[string]string) snout.Options {
+	lookup := snout.WithLookupEnv(func(name string) (string, bool) {
+		value, ok := env[name]
+
+		return value, ok
+	})
+
+	environ := snout.WithEnviron(func() []string {
+		pairs := make([]string, 0, len(env))
+		for name, value := range env {
+			pairs = append(pairs, name+"="+value)
+		}
+
+		return pairs
+	})
+
+	return func(kernel *snout.KernelOptions) {
+		lookup(kernel)
+		environ(kernel)
+	}
+}
+
+// Config waits for RunE to be called and returns the configuration it was handed. The test fails when the kernel
+// returns without calling RunE, as it does when the configuration is invalid.
+func (h *Harness[T]) Config() T {
+	h.t.Helper()
+
+	select {
+	case <-h.started:
+		return h.cfg
+	case <-h.done:
+		h.t.Fatalf("snouttest: kernel exited before running: %v", h.err)
+	case <-time.After(timeout):
+		h.t.Fatalf("snouttest: kernel did not run within %s", timeout)
+	}
+
+	return h.cfg
+}
+
+// WaitReady waits for RunE to mark the kernel ready with snout.Ready. The test fails when the kernel returns first.
+func (h *Harness[T]) WaitReady() {
+	h.t.Helper()
+
+	select {
+	case <-h.ready:
+	case <-h.done:
+		h.t.Fatalf("snouttest: kernel exited before becoming ready: %v", h.err)
+	case <-time.After(timeout):
+		h.t.Fatalf("snouttest: kernel did not become ready within %s", timeout)
+	}
+}
+
+// Signal delivers sig to the kernel as if the process had received it. It does nothing once the kernel returned.
+func (h *Harness[T]) Signal(sig os.Signal) {
+	select {
+	case h.signals <- sig:
+	case <-h.done:
+	}
+}
+
+// Shutdown sends SIGTERM to the kernel and waits for it to return.
+func (h *Harness[T]) Shutdown() error {
+	h.t.Helper()
+
+	h.Signal(syscall.SIGTERM)
+
+	return h.Wait()
+}
+
+// Wait waits for the kernel to return and returns the error of Initialize.
+func (h *Harness[T]) Wait() error {
+	h.t.Helper()
+
+	select {
+	case <-h.done:
+	case <-time.After(timeout):
+		h.t.Fatalf("snouttest: kernel did not return within %s", timeout)
+	}
+
+	return h.err
+}
+
+// Done returns a channel closed once the kernel returned.
+func (h *Harness[T]) Done() <-chan struct{} {
+	return h.done
+}
+
+// Output returns what snout printed so far, such as the --check-config and --print-config reports.
+func (h *Harness[T]) Output() string {
+	return h.output.String()
+}
+
+// configSource is a snout.Source serving a fixed set of values.
+type configSource map[string]any
+
+// Load implements snout.Source. The values are shared by every kernel started with them, as snout copies them before
+// merging.
+func (c configSource) Load(context.Context) (map[string]any, error) {
+	return c, nil
+}
+
+// buffer is a bytes.Buffer safe for concurrent use.
+type buffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+// Write implements io.Writer.
+func (b *buffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Write(p)
+}
+
+// String returns the content written so far.
+func (b *buffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.String()
+}
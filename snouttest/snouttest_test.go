@@ -0,0 +1,152 @@
+package snouttest_test
+
+import (
+	"context"
+	"errors"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/chiguirez/snout/v3"
+	"github.com/chiguirez/snout/v3/snouttest"
+)
+
+type harnessConfig struct {
+	Kafka struct {
+		Topic string `snout:"topic"`
+		Group string `snout:"group" default:"workers"`
+	} `snout:"kafka"`
+	Port int    `snout:"port" validate:"required"`
+	Mode string `snout:"mode" default:"serve"`
+}
+
+type snouttestSuite struct {
+	suite.Suite
+}
+
+func TestSnouttest(t *testing.T) {
+	suite.Run(t, new(snouttestSuite))
+}
+
+func (s *snouttestSuite) TestStart() {
+	s.Run("Given a kernel started with an explicit config map, env and args", func() {
+		kernel := snout.Kernel[harnessConfig]{
+			RunE: func(ctx context.Context, cfg harnessConfig) error {
+				snout.Ready(ctx)
+				<-ctx.Done()
+
+				return nil
+			},
+		}
+
+		h := snouttest.Start(s.T(), kernel,
+			snouttest.WithConfig(map[string]any{"kafka.topic": "orders", "port": 8080}),
+			snouttest.WithEnv(map[string]string{"APP_PORT": "9090"}),
+			snout.WithEnvVarPrefix("APP"),
+			snout.WithArgs("--mode=migrate"),
+		)
+
+		s.Run("When the kernel becomes ready", func() {
+			h.WaitReady()
+
+			s.Run("Then RunE is handed the configuration built from them", func() {
+				cfg := h.Config()
+				s.Equal("orders", cfg.Kafka.Topic)
+				s.Equal("workers", cfg.Kafka.Group)
+				s.Equal(9090, cfg.Port)
+				s.Equal("migrate", cfg.Mode)
+			})
+		})
+
+		s.Run("When it is shut down", func() {
+			err := h.Shutdown()
+
+			s.Run("Then RunE returns", func() {
+				s.NoError(err)
+				_, open := <-h.Done()
+				s.False(open)
+			})
+		})
+	})
+
+	s.Run("Given two kernels started side by side with different env", func() {
+		run := func(port string) *snouttest.Harness[harnessConfig] {
+			return snouttest.Start(s.T(), snout.Kernel[harnessConfig]{
+				RunE: func(ctx context.Context, cfg harnessConfig) error {
+					<-ctx.Done()
+
+					return nil
+				},
+			}, snouttest.WithEnv(map[string]string{"PORT": port}))
+		}
+
+		first, second := run("1"), run("2")
+
+		s.Run("When their configuration is read", func() {
+			s.Run("Then each only sees its own env", func() {
+				s.Equal(1, first.Config().Port)
+				s.Equal(2, second.Config().Port)
+			})
+		})
+	})
+}
+
+func (s *snouttestSuite) TestSignal() {
+	s.Run("Given a running kernel", func() {
+		received := make(chan struct{})
+
+		h := snouttest.Start(s.T(), snout.Kernel[harnessConfig]{
+			RunE: func(ctx context.Context, cfg harnessConfig) error {
+				<-ctx.Done()
+				close(received)
+
+				return errors.New("interrupted")
+			},
+		}, snouttest.WithEnv(map[string]string{"PORT": "1"}))
+
+		s.Run("When it receives SIGINT", func() {
+			h.Config()
+			h.Signal(syscall.SIGINT)
+
+			s.Run("Then its context is cancelled and the error of RunE returned", func() {
+				s.EqualError(h.Wait(), "interrupted")
+				_, open := <-received
+				s.False(open)
+			})
+		})
+	})
+}
+
+func (s *snouttestSuite) TestInvalidConfig() {
+	s.Run("Given a kernel whose required config is missing", func() {
+		h := snouttest.Start(s.T(), snout.Kernel[harnessConfig]{
+			RunE: func(ctx context.Context, cfg harnessConfig) error {
+				s.Fail("RunE should not run")
+
+				return nil
+			},
+		})
+
+		s.Run("When it is started", func() {
+			err := h.Wait()
+
+			s.Run("Then it returns a validation error", func() {
+				s.ErrorIs(err, snout.ErrValidation)
+			})
+		})
+	})
+
+	s.Run("Given a kernel started with --check-config", func() {
+		h := snouttest.Start(s.T(), snout.Kernel[harnessConfig]{}, snout.WithArgs("--check-config"))
+
+		s.Run("When it returns", func() {
+			err := h.Wait()
+
+			s.Run("Then the report is kept in its output", func() {
+				s.ErrorIs(err, snout.ErrValidation)
+				s.Contains(h.Output(), "config invalid: 1 keys failed validation")
+			})
+		})
+	})
+}
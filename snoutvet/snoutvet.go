@@ -0,0 +1,436 @@
+// Package snoutvet defines an analyzer checking the struct tags of the types used as snout.Kernel[T].
+//
+// It reports exported fields without snout tag, keys declared twice, default tags that don't parse into the type of
+// their field, validate tags the validator rejects, and keys whose env var names collide once dots are replaced with
+// underscores. The analyzer runs with go vet through the snoutvet command:
+//
+//	go install github.com/chiguirez/snout/v3/cmd/snoutvet@latest
+//	go vet -vettool=$(which snoutvet) ./...
+//
+// Kernels bootstrapped with WithTagName, WithKeyNaming or WithEnvKeySeparator are checked with the matching flags,
+// as in -tag=yaml, -key-naming=snake and -env-separator=__. Fields whose tag names them "-" are left out, as snout
+// does.
+package snoutvet
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/chiguirez/snout/v3"
+)
+
+// snoutPath is the import path of package snout.
+const snoutPath = "github.com/chiguirez/snout/v3"
+
+// Analyzer checks the struct tags of the types used as snout.Kernel[T].
+var Analyzer = &analysis.Analyzer{
+	Name: "snout",
+	Doc:  "check the snout struct tags of the types used as snout.Kernel[T]",
+	Run:  run,
+}
+
+// The naming the analyzer checks keys with, set by its flags.
+var (
+	tagName      = "snout"
+	keyNaming    = ""
+	envSeparator = "_"
+)
+
+// keyNamings maps the values of the -key-naming flag to the naming strategies of snout.
+var keyNamings = map[string]snout.NamingStrategy{
+	"snake": snout.SnakeCase,
+	"kebab": snout.KebabCase,
+	"camel": snout.CamelCase,
+}
+
+func init() {
+	Analyzer.Flags.StringVar(&tagName, "tag", tagName, "struct tag naming keys, as set with snout.WithTagName")
+	Analyzer.Flags.StringVar(&keyNaming, "key-naming", keyNaming,
+		"strategy naming the keys of untagged fields, snake, kebab or camel, as set with snout.WithKeyNaming")
+	Analyzer.Flags.StringVar(&envSeparator, "env-separator", envSeparator,
+		"separator of nested keys in env var names, as set with snout.WithEnvKeySeparator")
+}
+
+// run checks every type the package instantiates snout.Kernel with, once.
+func run(pass *analysis.Pass) (interface{}, error) {
+	strategy, ok := keyNamings[keyNaming]
+	if !ok && keyNaming != "" {
+		return nil, fmt.Errorf("unknown key naming %q, expecting snake, kebab or camel", keyNaming)
+	}
+
+	idents := make([]*ast.Ident, 0, len(pass.TypesInfo.Instances))
+
+	for ident, instance := range pass.TypesInfo.Instances {
+		if isKernel(pass.TypesInfo.Uses[ident]) && instance.TypeArgs.Len() == 1 {
+			idents = append(idents, ident)
+		}
+	}
+
+	sort.Slice(idents, func(i, j int) bool { return idents[i].Pos() < idents[j].Pos() })
+
+	checked := map[types.Type]bool{}
+
+	for _, ident := range idents {
+		t := pass.TypesInfo.Instances[ident].TypeArgs.At(0)
+		if checked[t] {
+			continue
+		}
+
+		checked[t] = true
+
+		if s, ok := indirect(t).Underlying().(*types.Struct); ok {
+			c := &checker{pass: pass, strategy: strategy, keys: map[string]string{}, envs: map[string]string{}}
+			c.checkStruct(s, "", ident.Pos())
+		}
+	}
+
+	return nil, nil
+}
+
+// isKernel reports whether obj is snout.Kernel.
+func isKernel(obj types.Object) bool {
+	return obj != nil && obj.Pkg() != nil && obj.Pkg().Path() == snoutPath && obj.Name() == "Kernel"
+}
+
+// checker checks the fields of a single configuration type.
+type checker struct {
+	pass     *analysis.Pass
+	strategy snout.NamingStrategy
+	keys     map[string]string
+	envs     map[string]string
+}
+
+// checkStruct checks the fields of s, whose key is path. Fields declared outside the package are reported at pos,
+// the position of the field or instantiation leading to them.
+func (c *checker) checkStruct(s *types.Struct, path string, pos token.Pos) {
+	for i := 0; i < s.NumFields(); i++ {
+		field := s.Field(i)
+		if !field.Exported() {
+			continue
+		}
+
+		fieldPos := pos
+		if field.Pkg() == c.pass.Pkg {
+			fieldPos = field.Pos()
+		}
+
+		tag := reflect.StructTag(s.Tag(i))
+
+		name, _, _ := strings.Cut(tag.Get(tagName), ",")
+		if name == "-" {
+			continue
+		}
+
+		if nested, ok := nestedStruct(field.Type()); ok && name == "" && field.Embedded() {
+			c.checkStruct(nested, path, fieldPos)
+
+			continue
+		}
+
+		if name == "" && c.strategy != nil {
+			name = c.strategy(field.Name())
+		}
+
+		if name == "" {
+			c.pass.Reportf(fieldPos, "field %s has no %s tag, leaving an empty segment in its key", field.Name(), tagName)
+
+			continue
+		}
+
+		key := name
+		if path != "" {
+			key = path + "." + name
+		}
+
+		if nested, ok := nestedStruct(field.Type()); ok {
+			c.checkStruct(nested, key, fieldPos)
+
+			continue
+		}
+
+		c.checkKey(key, tag, field.Name(), fieldPos)
+		c.checkDefault(tag, field.Type(), fieldPos)
+		c.checkValidate(tag, field.Type(), fieldPos)
+	}
+}
+
+// checkKey reports keys declared twice and env var names already bound to another key, named after the key or by
+// an env tag.
+func (c *checker) checkKey(key string, tag reflect.StructTag, fieldName string, pos token.Pos) {
+	if other, ok := c.keys[key]; ok {
+		c.pass.Reportf(pos, "duplicate key %s, already declared by field %s", key, other)
+
+		return
+	}
+
+	c.keys[key] = fieldName
+
+	env := tag.Get("env")
+
+	switch env {
+	case "-":
+		return
+	case "":
+		env = strings.ToUpper(strings.ReplaceAll(key, ".", envSeparator))
+	}
+
+	if other, ok := c.envs[env]; ok {
+		c.pass.Reportf(pos, "env var %s of key %s collides with key %s", env, key, other)
+
+		return
+	}
+
+	c.envs[env] = key
+}
+
+// checkDefault reports a default tag that doesn't parse into t.
+func (c *checker) checkDefault(tag reflect.StructTag, t types.Type, pos token.Pos) {
+	value := tag.Get("default")
+	if value == "" {
+		return
+	}
+
+	if err := parseDefault(value, t); err != nil {
+		c.pass.Reportf(pos, "default %q does not parse as %s: %v", value, types.TypeString(t, packageName), err)
+	}
+}
+
+// checkValidate reports a validate tag the validator rejects for t, either for an unknown rule or a bad parameter.
+// Rules comparing the field with other fields can't be checked out of their struct and are skipped.
+func (c *checker) checkValidate(tag reflect.StructTag, t types.Type, pos token.Pos) {
+	rules := tag.Get("validate")
+	if rules == "" {
+		return
+	}
+
+	rt := reflectType(t)
+	if rt == nil {
+		return
+	}
+
+	for _, value := range []reflect.Value{reflect.Zero(rt), sampleValue(rt)} {
+		if msg := validateRules(value, rules); msg != "" {
+			c.pass.Reportf(pos, "invalid validate tag %q: %s", rules, msg)
+
+			return
+		}
+	}
+}
+
+// validateRules runs the validator over value with rules, returning the panic message of a rejected tag.
+func validateRules(value reflect.Value, rules string) (msg string) {
+	defer func() {
+		if r := recover(); r != nil {
+			msg = fmt.Sprint(r)
+			if strings.Contains(msg, "Invalid field namespace") {
+				msg = ""
+			}
+		}
+	}()
+
+	_ = validator.New().Var(value.Interface(), rules)
+
+	return ""
+}
+
+// parseDefault parses value as snout decodes defaults into t: slices as a single item, commas included, durations
+// with time.ParseDuration and features as booleans or percentages. Other types are not checked.
+func parseDefault(value string, t types.Type) error {
+	switch {
+	case isNamed(t, "time", "Duration"):
+		_, err := time.ParseDuration(value)
+
+		return err
+	case isNamed(t, snoutPath, "Feature"):
+		return parseFeature(value)
+	}
+
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		return parseBasic(value, u)
+	case *types.Slice:
+		return parseDefault(value, u.Elem())
+	case *types.Pointer:
+		return parseDefault(value, u.Elem())
+	}
+
+	return nil
+}
+
+// parseBasic parses value into the basic type t.
+func parseBasic(value string, t *types.Basic) error {
+	var err error
+
+	switch info := t.Info(); {
+	case info&types.IsBoolean != 0:
+		_, err = strconv.ParseBool(value)
+	case info&types.IsUnsigned != 0:
+		_, err = strconv.ParseUint(value, 0, bitSize(t))
+	case info&types.IsInteger != 0:
+		_, err = strconv.ParseInt(value, 0, bitSize(t))
+	case info&types.IsFloat != 0:
+		_, err = strconv.ParseFloat(value, bitSize(t))
+	}
+
+	if numErr, ok := err.(*strconv.NumError); ok {
+		return numErr.Err
+	}
+
+	return err
+}
+
+// parseFeature parses the value of a snout.Feature: a boolean or a percentage between 0 and 100.
+func parseFeature(value string) error {
+	if _, err := strconv.ParseBool(value); err == nil {
+		return nil
+	}
+
+	percentage, err := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+	if err != nil || percentage < 0 || percentage > 100 {
+		return errors.New("expecting true, false or a percentage between 0 and 100")
+	}
+
+	return nil
+}
+
+// bitSize returns the size in bits of the basic numeric type t.
+func bitSize(t *types.Basic) int {
+	switch t.Kind() {
+	case types.Int8, types.Uint8:
+		return 8
+	case types.Int16, types.Uint16:
+		return 16
+	case types.Int32, types.Uint32, types.Float32:
+		return 32
+	default:
+		return 64
+	}
+}
+
+// reflectType returns the reflect type standing for t when validating, or nil when there is none.
+func reflectType(t types.Type) reflect.Type {
+	switch {
+	case isNamed(t, "time", "Duration"):
+		return reflect.TypeOf(time.Duration(0))
+	case isNamed(t, "time", "Time"):
+		return reflect.TypeOf(time.Time{})
+	}
+
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		return basicTypes[u.Kind()]
+	case *types.Pointer:
+		if elem := reflectType(u.Elem()); elem != nil {
+			return reflect.PointerTo(elem)
+		}
+	case *types.Slice:
+		if elem := reflectType(u.Elem()); elem != nil {
+			return reflect.SliceOf(elem)
+		}
+	case *types.Map:
+		key, elem := reflectType(u.Key()), reflectType(u.Elem())
+		if key != nil && elem != nil {
+			return reflect.MapOf(key, elem)
+		}
+	}
+
+	return nil
+}
+
+// basicTypes maps basic type kinds to their reflect types.
+var basicTypes = map[types.BasicKind]reflect.Type{
+	types.Bool:    reflect.TypeOf(false),
+	types.Int:     reflect.TypeOf(0),
+	types.Int8:    reflect.TypeOf(int8(0)),
+	types.Int16:   reflect.TypeOf(int16(0)),
+	types.Int32:   reflect.TypeOf(int32(0)),
+	types.Int64:   reflect.TypeOf(int64(0)),
+	types.Uint:    reflect.TypeOf(uint(0)),
+	types.Uint8:   reflect.TypeOf(uint8(0)),
+	types.Uint16:  reflect.TypeOf(uint16(0)),
+	types.Uint32:  reflect.TypeOf(uint32(0)),
+	types.Uint64:  reflect.TypeOf(uint64(0)),
+	types.Float32: reflect.TypeOf(float32(0)),
+	types.Float64: reflect.TypeOf(float64(0)),
+	types.String:  reflect.TypeOf(""),
+}
+
+// sampleValue returns a non zero value of t, so that rules after omitempty get to run and parse their parameters.
+func sampleValue(t reflect.Type) reflect.Value {
+	v := reflect.New(t).Elem()
+
+	switch t.Kind() {
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(1)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(1)
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(1)
+	case reflect.String:
+		v.SetString("a")
+	case reflect.Ptr:
+		v.Set(reflect.New(t.Elem()))
+		v.Elem().Set(sampleValue(t.Elem()))
+	case reflect.Slice:
+		v.Set(reflect.Append(v, sampleValue(t.Elem())))
+	case reflect.Map:
+		v.Set(reflect.MakeMap(t))
+		v.SetMapIndex(sampleValue(t.Key()), sampleValue(t.Elem()))
+	}
+
+	return v
+}
+
+// nestedStruct returns the struct t, or t points to, when it holds further configuration keys as snout walks it.
+func nestedStruct(t types.Type) (*types.Struct, bool) {
+	t = indirect(t)
+	if isNamed(t, "time", "Time") || isNamed(t, snoutPath, "Feature") {
+		return nil, false
+	}
+
+	s, ok := t.Underlying().(*types.Struct)
+
+	return s, ok
+}
+
+// indirect removes any pointer indirection from t.
+func indirect(t types.Type) types.Type {
+	for {
+		p, ok := t.Underlying().(*types.Pointer)
+		if !ok {
+			return t
+		}
+
+		t = p.Elem()
+	}
+}
+
+// packageName qualifies types by the name of their package in messages, as in time.Duration.
+func packageName(pkg *types.Package) string {
+	return pkg.Name()
+}
+
+// isNamed reports whether t is the named type pkg.name.
+func isNamed(t types.Type, pkg, name string) bool {
+	named, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+
+	obj := named.Obj()
+
+	return obj.Pkg() != nil && obj.Pkg().Path() == pkg && obj.Name() == name
+}
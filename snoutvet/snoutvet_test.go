@@ -0,0 +1,50 @@
+package snoutvet_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/chiguirez/snout/v3/snoutvet"
+)
+
+type snoutvetSuite struct {
+	suite.Suite
+}
+
+func TestSnoutvet(t *testing.T) {
+	suite.Run(t, new(snoutvetSuite))
+}
+
+func (s *snoutvetSuite) TestAnalyzer() {
+	s.Run("Given a package using a config Struct with broken tags as a Kernel", func() {
+		s.Run("When it is analyzed", func() {
+			s.Run("Then every broken tag is reported", func() {
+				analysistest.Run(s.T(), analysistest.TestData(), snoutvet.Analyzer, "a")
+			})
+		})
+	})
+}
+
+func (s *snoutvetSuite) TestAnalyzerNaming() {
+	s.Run("Given a package using a config Struct named by yaml tags and snake case as a Kernel", func() {
+		flags := map[string]string{"tag": "yaml", "key-naming": "snake", "env-separator": "__"}
+
+		for name, value := range flags {
+			s.Require().NoError(snoutvet.Analyzer.Flags.Set(name, value))
+		}
+
+		defer func() {
+			for name := range flags {
+				s.Require().NoError(snoutvet.Analyzer.Flags.Set(name, snoutvet.Analyzer.Flags.Lookup(name).DefValue))
+			}
+		}()
+
+		s.Run("When it is analyzed with the matching flags", func() {
+			s.Run("Then keys are named as snout names them", func() {
+				analysistest.Run(s.T(), analysistest.TestData(), snoutvet.Analyzer, "b")
+			})
+		})
+	})
+}
@@ -0,0 +1,44 @@
+package a
+
+import (
+	"time"
+
+	"github.com/chiguirez/snout/v3"
+)
+
+type Config struct {
+	Kafka struct {
+		Topic   string `snout:"topic" validate:"required"`
+		Brokers string `snout:"brokers" validate:"requird"` // want `invalid validate tag "requird": Undefined validation function 'requird' on field ''`
+		Retries int    `snout:"retries" default:"abc"`      // want `default "abc" does not parse as int: invalid syntax`
+		Size    int8   `snout:"size" default:"300"`         // want `default "300" does not parse as int8: value out of range`
+		Topics  []int  `snout:"topics" default:"1,x"`       // want `default "1,x" does not parse as \[\]int: invalid syntax`
+		Ports   []int  `snout:"ports" default:"1,2"`        // want `default "1,2" does not parse as \[\]int: invalid syntax`
+		Codes   []int  `snout:"codes" default:"7"`
+		Timeout int    `snout:"timeout" validate:"omitempty,min=x"` // want `invalid validate tag "omitempty,min=x": strconv.ParseInt: parsing "x": invalid syntax`
+		Mode    string `snout:"mode" validate:"required_if=Topic x"`
+	} `snout:"kafka"`
+	KafkaTopic string        `snout:"kafka_topic"` // want `env var KAFKA_TOPIC of key kafka_topic collides with key kafka.topic`
+	Topic      string        `snout:"topic"`
+	Again      string        `snout:"topic"`               // want `duplicate key topic, already declared by field Topic`
+	Wait       time.Duration `snout:"wait" default:"1x"`   // want `default "1x" does not parse as time.Duration: time: unknown unit "x" in duration "1x"`
+	Beta       snout.Feature `snout:"beta" default:"150%"` // want `default "150%" does not parse as snout.Feature: expecting true, false or a percentage between 0 and 100`
+	Missing    string        // want `field Missing has no snout tag, leaving an empty segment in its key`
+	Good       *int          `snout:"good" default:"0x10" validate:"omitempty,gte=1"`
+	Port       int           `snout:"port" env:"PORT"`
+	HTTPPort   int           `snout:"http_port" env:"PORT"` // want `env var PORT of key http_port collides with key port`
+	Internal   string        `snout:"kafka_brokers" env:"-"`
+	Skipped    string        `snout:"-"`
+	Ignored    string        `snout:"-"`
+	Shared
+	hidden string
+}
+
+type Shared struct {
+	Region string `snout:"region"`
+	Wait   string `snout:"wait"` // want `duplicate key wait, already declared by field Wait`
+}
+
+var kernel = snout.Kernel[Config]{}
+
+var again = snout.Kernel[Config]{}
@@ -0,0 +1,19 @@
+package b
+
+import (
+	"github.com/chiguirez/snout/v3"
+)
+
+type Config struct {
+	Kafka struct {
+		BrokerAddress string `yaml:"broker_address,omitempty"`
+		Retries       int    `yaml:"retries" default:"abc"` // want `default "abc" does not parse as int: invalid syntax`
+	} `yaml:"kafka"`
+	KafkaBrokerAddress string `yaml:"kafka_broker_address"`
+	HTTPPort           int
+	HttpPort           int    // want `duplicate key http_port, already declared by field HTTPPort`
+	Internal           string `yaml:"-"`
+	Secret             string `yaml:"-"`
+}
+
+var kernel = snout.Kernel[Config]{}
@@ -0,0 +1,5 @@
+package snout
+
+type Kernel[T any] struct{}
+
+type Feature struct{}
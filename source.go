@@ -0,0 +1,102 @@
+package snout
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Source is a configuration source loaded alongside config files, env vars and flags.
+type Source interface {
+	// Load returns the configuration held by the source, either as nested maps or keyed by dotted snout keys. The
+	// kernel copies the result, so sources may return the same values on every call.
+	Load(ctx context.Context) (map[string]any, error)
+}
+
+// Watcher is implemented by sources able to tell when the configuration they hold changes.
+type Watcher interface {
+	// Watch blocks until ctx is done, calling onChange whenever the configuration held by the source changes.
+	Watch(ctx context.Context, onChange func()) error
+}
+
+// WithSource registers a configuration source in KernelOptions. Sources take precedence over config files and are
+// overridden by env vars and flags; sources registered later take precedence over earlier ones.
+func WithSource(source Source) Options {
+	return func(kernel *KernelOptions) {
+		kernel.Sources = append(kernel.Sources, source)
+	}
+}
+
+// loadSources loads every source in order, returning their configuration as nested maps.
+func loadSources(ctx context.Context, sources []Source) ([]map[string]any, error) {
+	loaded := make([]map[string]any, 0, len(sources))
+
+	for _, source := range sources {
+		values, err := source.Load(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("loading source %T: %w", source, err)
+		}
+
+		loaded = append(loaded, nestKeys(values))
+	}
+
+	return loaded, nil
+}
+
+// nestKeys turns dotted keys of m, at any depth, into nested maps. The result shares no maps or slices with m, as
+// the kernel merges values in place and sources may hand out the same values on every load.
+func nestKeys(m map[string]any) map[string]any {
+	nested := map[string]any{}
+
+	for key, value := range m {
+		mergeNested(nested, strings.Split(key, "."), copyNested(value))
+	}
+
+	return nested
+}
+
+// copyNested returns a deep copy of value, nesting the dotted keys of the maps within it.
+func copyNested(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		return nestKeys(v)
+	case []any:
+		items := make([]any, len(v))
+
+		for i, item := range v {
+			items[i] = copyNested(item)
+		}
+
+		return items
+	default:
+		return value
+	}
+}
+
+// mergeNested stores value in m under path, merging maps found on both sides.
+func mergeNested(m map[string]any, path []string, value any) {
+	for _, segment := range path[:len(path)-1] {
+		child, ok := m[segment].(map[string]any)
+		if !ok {
+			child = map[string]any{}
+			m[segment] = child
+		}
+
+		m = child
+	}
+
+	key := path[len(path)-1]
+
+	existing, existingIsMap := m[key].(map[string]any)
+	incoming, incomingIsMap := value.(map[string]any)
+
+	if existingIsMap && incomingIsMap {
+		for k, v := range incoming {
+			mergeNested(existing, []string{k}, v)
+		}
+
+		return
+	}
+
+	m[key] = value
+}
@@ -0,0 +1,144 @@
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultConsulWait is the blocking query wait used by Consul.Watch when none is configured.
+const defaultConsulWait = 5 * time.Minute
+
+// Consul is a source loading every key below a prefix from the Consul KV HTTP API. A key such as
+// config/orders/kafka/topic below the prefix config/orders/ maps to the snout key kafka.topic.
+type Consul struct {
+	// Address of the Consul agent, e.g. http://127.0.0.1:8500.
+	Address string
+	// Prefix of the keys holding the configuration, e.g. config/orders/.
+	Prefix string
+	// Token is sent as X-Consul-Token when set.
+	Token string
+	// Client sends the requests, http.DefaultClient when nil.
+	Client *http.Client
+	// Wait is the maximum duration of the blocking queries issued by Watch, 5m when zero.
+	Wait time.Duration
+	// Interval between polls in Watch when the agent answers without X-Consul-Index, 30s when zero.
+	Interval time.Duration
+}
+
+// consulEntry is an entry of a Consul KV recurse response.
+type consulEntry struct {
+	Key   string
+	Value []byte
+}
+
+// Load implements snout.Source.
+func (c Consul) Load(ctx context.Context) (map[string]any, error) {
+	values, _, err := c.load(ctx, "")
+
+	return values, err
+}
+
+// Watch implements snout.Watcher using Consul blocking queries. Queries returning without the index moving are
+// spaced by a second, an index going backwards is notified as a change and starts over, and agents answering without
+// X-Consul-Index are polled every Interval instead.
+func (c Consul) Watch(ctx context.Context, onChange func()) error {
+	blockFor := c.Wait
+	if blockFor <= 0 {
+		blockFor = defaultConsulWait
+	}
+
+	_, index, err := c.load(ctx, "")
+	if err != nil {
+		index = 0
+	}
+
+	for {
+		query := ""
+		if index != 0 {
+			query = "index=" + strconv.FormatUint(index, 10) + "&wait=" + blockFor.String()
+		}
+
+		_, next, err := c.load(ctx, query)
+
+		switch {
+		case ctx.Err() != nil:
+			return ctx.Err()
+		case err != nil:
+			if err := wait(ctx, retryDelay); err != nil {
+				return err
+			}
+		case next == 0:
+			return poll(ctx, c.Interval, c.Load, onChange)
+		case next == index:
+			if err := wait(ctx, retryDelay); err != nil {
+				return err
+			}
+		case next < index:
+			// an index going backwards, e.g. after a snapshot restore, may come with any change
+			onChange()
+
+			index = 0
+		default:
+			if index != 0 {
+				onChange()
+			}
+
+			index = next
+		}
+	}
+}
+
+// load reads the keys below the prefix, returning them along with the X-Consul-Index of the response.
+func (c Consul) load(ctx context.Context, query string) (map[string]any, uint64, error) {
+	u := strings.TrimRight(c.Address, "/") + "/v1/kv/" + strings.TrimLeft(c.Prefix, "/") + "?recurse=true"
+	if query != "" {
+		u += "&" + query
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, http.NoBody)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if c.Token != "" {
+		req.Header.Set("X-Consul-Token", c.Token)
+	}
+
+	body, resp, err := do(c.Client, req)
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return map[string]any{}, consulIndex(resp), nil
+	}
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var entries []consulEntry
+	if err := json.Unmarshal(body, &entries); err != nil {
+		return nil, 0, fmt.Errorf("decoding %s: %w", req.URL.Redacted(), err)
+	}
+
+	values := map[string]any{}
+
+	for _, entry := range entries {
+		if entry.Value == nil || strings.HasSuffix(entry.Key, "/") {
+			continue
+		}
+
+		values[keyFromPath(entry.Key, strings.TrimLeft(c.Prefix, "/"))] = string(entry.Value)
+	}
+
+	return values, consulIndex(resp), nil
+}
+
+// consulIndex returns the X-Consul-Index of a response.
+func consulIndex(resp *http.Response) uint64 {
+	index, _ := strconv.ParseUint(resp.Header.Get("X-Consul-Index"), 10, 64)
+
+	return index
+}
@@ -0,0 +1,93 @@
+package source
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Etcd is a source loading every key below a prefix through the etcd v3 JSON gateway. A key such as
+// /config/orders/kafka/topic below the prefix /config/orders/ maps to the snout key kafka.topic.
+type Etcd struct {
+	// Endpoint of the etcd gateway, e.g. http://127.0.0.1:2379.
+	Endpoint string
+	// Prefix of the keys holding the configuration, e.g. /config/orders/.
+	Prefix string
+	// Client sends the requests, http.DefaultClient when nil.
+	Client *http.Client
+	// Interval between polls in Watch, 30s when zero.
+	Interval time.Duration
+}
+
+// etcdRangeRequest is the body of a /v3/kv/range request. Keys are base64 encoded by encoding/json.
+type etcdRangeRequest struct {
+	Key      []byte `json:"key"`
+	RangeEnd []byte `json:"range_end"`
+}
+
+// etcdRangeResponse is the body of a /v3/kv/range response.
+type etcdRangeResponse struct {
+	Kvs []struct {
+		Key   []byte `json:"key"`
+		Value []byte `json:"value"`
+	} `json:"kvs"`
+}
+
+// Load implements snout.Source.
+func (e Etcd) Load(ctx context.Context) (map[string]any, error) {
+	reqBody, err := json.Marshal(etcdRangeRequest{Key: []byte(e.Prefix), RangeEnd: prefixEnd(e.Prefix)})
+	if err != nil {
+		return nil, err
+	}
+
+	u := strings.TrimRight(e.Endpoint, "/") + "/v3/kv/range"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	body, _, err := do(e.Client, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp etcdRangeResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", req.URL.Redacted(), err)
+	}
+
+	values := map[string]any{}
+
+	for _, kv := range resp.Kvs {
+		values[keyFromPath(string(kv.Key), e.Prefix)] = string(kv.Value)
+	}
+
+	return values, nil
+}
+
+// Watch implements snout.Watcher by polling the keys every Interval.
+func (e Etcd) Watch(ctx context.Context, onChange func()) error {
+	return poll(ctx, e.Interval, e.Load, onChange)
+}
+
+// prefixEnd returns the range end matching every key starting with prefix.
+func prefixEnd(prefix string) []byte {
+	end := []byte(prefix)
+
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+
+			return end[:i+1]
+		}
+	}
+
+	return []byte{0}
+}
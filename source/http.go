@@ -0,0 +1,52 @@
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// HTTP is a source loading a JSON object from an HTTP endpoint with a GET request.
+type HTTP struct {
+	// URL of the endpoint.
+	URL string
+	// Header is sent along with every request, e.g. to authenticate.
+	Header http.Header
+	// Client sends the requests, http.DefaultClient when nil.
+	Client *http.Client
+	// Interval between polls in Watch, 30s when zero.
+	Interval time.Duration
+}
+
+// Load implements snout.Source.
+func (h HTTP) Load(ctx context.Context) (map[string]any, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.URL, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, values := range h.Header {
+		req.Header[name] = values
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	body, _, err := do(h.Client, req)
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]any{}
+	if err := json.Unmarshal(body, &values); err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", req.URL.Redacted(), err)
+	}
+
+	return values, nil
+}
+
+// Watch implements snout.Watcher by polling the endpoint every Interval.
+func (h HTTP) Watch(ctx context.Context, onChange func()) error {
+	return poll(ctx, h.Interval, h.Load, onChange)
+}
@@ -0,0 +1,113 @@
+// Package source provides snout configuration sources backed by remote services: a plain HTTP JSON endpoint, the
+// Consul KV HTTP API and the etcd v3 JSON gateway.
+//
+// Every source satisfies snout.Source and snout.Watcher and is registered with snout.WithSource.
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// defaultInterval is the polling interval used by Watch when none is configured.
+const defaultInterval = 30 * time.Second
+
+// retryDelay is the time Watch waits before retrying after a failed request.
+const retryDelay = time.Second
+
+// poll calls load every interval until ctx is done, calling onChange whenever the loaded configuration differs from
+// the previous one. Failed loads are retried on the next tick.
+func poll(
+	ctx context.Context,
+	interval time.Duration,
+	load func(ctx context.Context) (map[string]any, error),
+	onChange func(),
+) error {
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	last, _ := fingerprint(load(ctx))
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			current, err := fingerprint(load(ctx))
+			if err != nil {
+				continue
+			}
+
+			if current != last {
+				last = current
+
+				onChange()
+			}
+		}
+	}
+}
+
+// fingerprint returns a canonical representation of loaded configuration, for change detection.
+func fingerprint(values map[string]any, err error) (string, error) {
+	if err != nil {
+		return "", err
+	}
+
+	out, err := json.Marshal(values)
+
+	return string(out), err
+}
+
+// do sends req with client, or http.DefaultClient when nil, and returns the response body of a 200 response.
+func do(client *http.Client, req *http.Request) ([]byte, *http.Response, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return body, resp, fmt.Errorf("%s %s: unexpected status %s", req.Method, req.URL.Redacted(), resp.Status)
+	}
+
+	return body, resp, nil
+}
+
+// keyFromPath maps a slash separated KV path below prefix to a dotted snout key.
+func keyFromPath(path, prefix string) string {
+	path = strings.TrimPrefix(path, prefix)
+	path = strings.Trim(path, "/")
+
+	return strings.ReplaceAll(path, "/", ".")
+}
+
+// wait blocks for d or until ctx is done.
+func wait(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
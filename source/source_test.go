@@ -0,0 +1,370 @@
+package source_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/chiguirez/snout/v3"
+	"github.com/chiguirez/snout/v3/source"
+)
+
+var (
+	_ snout.Watcher = source.HTTP{}
+	_ snout.Watcher = source.Consul{}
+	_ snout.Watcher = source.Etcd{}
+)
+
+type sourceSuite struct {
+	suite.Suite
+}
+
+func TestSource(t *testing.T) {
+	suite.Run(t, new(sourceSuite))
+}
+
+func (s *sourceSuite) TestHTTPLoad() {
+	s.Run("Given an HTTP endpoint serving a JSON object", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer token" {
+				w.WriteHeader(http.StatusUnauthorized)
+
+				return
+			}
+
+			_, _ = w.Write([]byte(`{"kafka": {"topic": "orders"}, "app.port": 8080}`))
+		}))
+		defer server.Close()
+
+		s.Run("When the source is Loaded", func() {
+			values, err := source.HTTP{
+				URL:    server.URL,
+				Header: http.Header{"Authorization": []string{"Bearer token"}},
+			}.Load(context.TODO())
+
+			s.Run("Then the object is returned", func() {
+				s.Require().NoError(err)
+				s.Require().Equal(map[string]any{
+					"kafka":    map[string]any{"topic": "orders"},
+					"app.port": float64(8080),
+				}, values)
+			})
+		})
+	})
+}
+
+func (s *sourceSuite) TestHTTPLoadError() {
+	s.Run("Given an HTTP endpoint failing", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		s.Run("When the source is Loaded", func() {
+			_, err := source.HTTP{URL: server.URL}.Load(context.TODO())
+
+			s.Run("Then an error with the status is returned", func() {
+				s.Require().ErrorContains(err, "500 Internal Server Error")
+			})
+		})
+	})
+}
+
+func (s *sourceSuite) TestHTTPWatch() {
+	s.Run("Given an HTTP endpoint whose content changes", func() {
+		var requests, version atomic.Int32
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = fmt.Fprintf(w, `{"version": %d}`, version.Load())
+			requests.Add(1)
+		}))
+		defer server.Close()
+
+		s.Run("When the source is Watched", func() {
+			ctx, cancel := context.WithCancel(context.TODO())
+			defer cancel()
+
+			changed := make(chan struct{}, 1)
+
+			go func() {
+				_ = source.HTTP{URL: server.URL, Interval: 10 * time.Millisecond}.Watch(ctx, func() {
+					changed <- struct{}{}
+				})
+			}()
+
+			// The first load, the baseline changes are told from, read the old version.
+			s.Require().Eventually(func() bool { return requests.Load() >= 1 }, time.Second, time.Millisecond)
+			version.Store(1)
+
+			s.Run("Then the change is notified", func() {
+				select {
+				case <-changed:
+				case <-time.After(time.Second):
+					s.Fail("change not notified")
+				}
+			})
+		})
+	})
+}
+
+func (s *sourceSuite) TestConsulLoad() {
+	s.Run("Given a Consul KV API holding keys below a prefix", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			s.Equal("/v1/kv/config/orders/", r.URL.Path)
+			s.Equal("true", r.URL.Query().Get("recurse"))
+			s.Equal("secret", r.Header.Get("X-Consul-Token"))
+
+			w.Header().Set("X-Consul-Index", "7")
+			_ = json.NewEncoder(w).Encode([]map[string]any{
+				{"Key": "config/orders/", "Value": nil},
+				{"Key": "config/orders/kafka/topic", "Value": base64.StdEncoding.EncodeToString([]byte("orders"))},
+				{"Key": "config/orders/kafka/retries", "Value": base64.StdEncoding.EncodeToString([]byte("3"))},
+			})
+		}))
+		defer server.Close()
+
+		s.Run("When the source is Loaded", func() {
+			values, err := source.Consul{Address: server.URL, Prefix: "config/orders/", Token: "secret"}.
+				Load(context.TODO())
+
+			s.Run("Then keys are mapped below the prefix", func() {
+				s.Require().NoError(err)
+				s.Require().Equal(map[string]any{
+					"kafka.topic":   "orders",
+					"kafka.retries": "3",
+				}, values)
+			})
+		})
+	})
+}
+
+func (s *sourceSuite) TestConsulWatch() {
+	s.Run("Given a Consul KV API whose index moves", func() {
+		var requests, index atomic.Int32
+
+		index.Store(1)
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("index") == fmt.Sprint(index.Load()) {
+				time.Sleep(10 * time.Millisecond)
+			}
+
+			w.Header().Set("X-Consul-Index", fmt.Sprint(index.Load()))
+			_, _ = w.Write([]byte(`[]`))
+			requests.Add(1)
+		}))
+		defer server.Close()
+
+		s.Run("When the source is Watched", func() {
+			ctx, cancel := context.WithCancel(context.TODO())
+			defer cancel()
+
+			changed := make(chan struct{}, 1)
+
+			go func() {
+				_ = source.Consul{Address: server.URL, Prefix: "config"}.Watch(ctx, func() {
+					changed <- struct{}{}
+				})
+			}()
+
+			// The first load, the index blocking queries wait on, read the old index.
+			s.Require().Eventually(func() bool { return requests.Load() >= 1 }, time.Second, time.Millisecond)
+			index.Store(2)
+
+			s.Run("Then the change is notified", func() {
+				select {
+				case <-changed:
+				case <-time.After(time.Second):
+					s.Fail("change not notified")
+				}
+			})
+		})
+	})
+}
+
+func (s *sourceSuite) TestConsulWatchIndexReset() {
+	s.Run("Given a Consul KV API whose index goes backwards", func() {
+		var requests, index atomic.Int32
+
+		index.Store(5)
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("index") == fmt.Sprint(index.Load()) {
+				time.Sleep(10 * time.Millisecond)
+			}
+
+			w.Header().Set("X-Consul-Index", fmt.Sprint(index.Load()))
+			_, _ = w.Write([]byte(`[]`))
+			requests.Add(1)
+		}))
+		defer server.Close()
+
+		s.Run("When the source is Watched", func() {
+			ctx, cancel := context.WithCancel(context.TODO())
+			defer cancel()
+
+			changed := make(chan struct{}, 1)
+
+			go func() {
+				_ = source.Consul{Address: server.URL, Prefix: "config"}.Watch(ctx, func() {
+					select {
+					case changed <- struct{}{}:
+					default:
+					}
+				})
+			}()
+
+			// The first load, the index blocking queries wait on, read the index before the reset.
+			s.Require().Eventually(func() bool { return requests.Load() >= 1 }, time.Second, time.Millisecond)
+			index.Store(3)
+
+			s.Run("Then the reset is notified as a change", func() {
+				select {
+				case <-changed:
+				case <-time.After(time.Second):
+					s.Fail("reset not notified")
+				}
+			})
+		})
+	})
+}
+
+func (s *sourceSuite) TestConsulWatchWithoutIndexMoving() {
+	s.Run("Given a Consul KV API answering at once with the same index", func() {
+		var requests atomic.Int32
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			requests.Add(1)
+			w.Header().Set("X-Consul-Index", "7")
+			_, _ = w.Write([]byte(`[]`))
+		}))
+		defer server.Close()
+
+		s.Run("When the source is Watched", func() {
+			ctx, cancel := context.WithTimeout(context.TODO(), 200*time.Millisecond)
+			defer cancel()
+
+			err := source.Consul{Address: server.URL, Prefix: "config"}.Watch(ctx, func() {})
+
+			s.Run("Then queries are spaced rather than sent in a busy loop", func() {
+				s.Require().ErrorIs(err, context.DeadlineExceeded)
+				s.Require().LessOrEqual(requests.Load(), int32(2))
+			})
+		})
+	})
+}
+
+func (s *sourceSuite) TestConsulWatchWithoutIndex() {
+	s.Run("Given a Consul KV API answering without X-Consul-Index", func() {
+		var requests, version atomic.Int32
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_ = json.NewEncoder(w).Encode([]map[string]any{
+				{"Key": "config/version", "Value": base64.StdEncoding.EncodeToString([]byte(fmt.Sprint(version.Load())))},
+			})
+			requests.Add(1)
+		}))
+		defer server.Close()
+
+		s.Run("When the source is Watched", func() {
+			ctx, cancel := context.WithCancel(context.TODO())
+			defer cancel()
+
+			changed := make(chan struct{}, 1)
+
+			go func() {
+				_ = source.Consul{Address: server.URL, Prefix: "config", Interval: 10 * time.Millisecond}.Watch(ctx,
+					func() {
+						changed <- struct{}{}
+					})
+			}()
+
+			// The first load, the query answered without index and the first poll read the old version.
+			s.Require().Eventually(func() bool { return requests.Load() >= 3 }, time.Second, time.Millisecond)
+			version.Store(1)
+
+			s.Run("Then it falls back to polling and the change is notified", func() {
+				select {
+				case <-changed:
+				case <-time.After(time.Second):
+					s.Fail("change not notified")
+				}
+			})
+		})
+	})
+}
+
+func (s *sourceSuite) TestEtcdLoad() {
+	s.Run("Given an etcd gateway holding keys below a prefix", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var req struct {
+				Key      []byte `json:"key"`
+				RangeEnd []byte `json:"range_end"`
+			}
+
+			s.Equal("/v3/kv/range", r.URL.Path)
+			s.Require().NoError(json.NewDecoder(r.Body).Decode(&req))
+			s.Equal("/config/orders/", string(req.Key))
+			s.Equal("/config/orders0", string(req.RangeEnd))
+
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"kvs": []map[string]any{
+					{"key": []byte("/config/orders/kafka/topic"), "value": []byte("orders")},
+				},
+			})
+		}))
+		defer server.Close()
+
+		s.Run("When the source is Loaded", func() {
+			values, err := source.Etcd{Endpoint: server.URL, Prefix: "/config/orders/"}.Load(context.TODO())
+
+			s.Run("Then keys are mapped below the prefix", func() {
+				s.Require().NoError(err)
+				s.Require().Equal(map[string]any{"kafka.topic": "orders"}, values)
+			})
+		})
+	})
+}
+
+func (s *sourceSuite) TestKernelWithSource() {
+	s.Run("Given a kernel with an HTTP source", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"kafka": {"topic": "orders", "retries": 5}}`))
+		}))
+		defer server.Close()
+
+		type config struct {
+			Kafka struct {
+				Topic   string `snout:"topic"`
+				Retries int    `snout:"retries" default:"3"`
+			} `snout:"kafka"`
+		}
+
+		s.Run("When Kernel is Initialized", func() {
+			cfgChan := make(chan config, 1)
+
+			kernel := snout.Kernel[config]{RunE: func(_ context.Context, cfg config) error {
+				cfgChan <- cfg
+
+				return nil
+			}}
+
+			err := kernel.Bootstrap(context.TODO(), snout.WithSource(source.HTTP{URL: server.URL})).Initialize()
+			s.Require().NoError(err)
+
+			s.Run("Then values come from the source", func() {
+				cfg := <-cfgChan
+				s.Require().Equal("orders", cfg.Kafka.Topic)
+				s.Require().Equal(5, cfg.Kafka.Retries)
+			})
+		})
+	})
+}
@@ -0,0 +1,119 @@
+package snout_test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/chiguirez/snout/v3"
+)
+
+type stubSource map[string]any
+
+func (s stubSource) Load(context.Context) (map[string]any, error) {
+	return s, nil
+}
+
+type failingSource struct{}
+
+func (failingSource) Load(context.Context) (map[string]any, error) {
+	return nil, errors.New("unreachable")
+}
+
+func (s *snoutSuite) TestSourcePrecedence() {
+	s.Run("Given a YAML file, two sources and an env var", func() {
+		type stubConfig struct {
+			A string `snout:"a"`
+			B int    `snout:"b"`
+			C bool   `snout:"c"`
+			D *struct {
+				A *string  `snout:"a"`
+				B *float64 `snout:"b"`
+				C *bool    `snout:"c"`
+			} `snout:"d"`
+		}
+
+		s.T().Setenv("SOURCE_C", "false")
+
+		s.Run("When Kernel is Initialized", func() {
+			cfgChan := make(chan stubConfig, 1)
+
+			kernel := snout.Kernel[stubConfig]{RunE: func(_ context.Context, config stubConfig) error {
+				cfgChan <- config
+
+				return nil
+			}}
+
+			err := kernel.Bootstrap(
+				context.TODO(),
+				snout.WithServiceName("YAML"),
+				snout.WithEnvVarFolderLocation("./testdata/"),
+				snout.WithEnvVarPrefix("SOURCE"),
+				snout.WithSource(stubSource{"a": "first", "d.a": "first"}),
+				snout.WithSource(stubSource{"a": "second", "d": map[string]any{"b": 2.5}}),
+			).Initialize()
+			s.Require().NoError(err)
+
+			s.Run("Then later sources win over earlier ones and files, and env vars win over sources", func() {
+				config := <-cfgChan
+				s.Require().Equal("second", config.A)
+				s.Require().Equal(1, config.B)
+				s.Require().False(config.C)
+				s.Require().Equal("first", *config.D.A)
+				s.Require().Equal(2.5, *config.D.B)
+				s.Require().False(*config.D.C)
+			})
+		})
+	})
+}
+
+func (s *snoutSuite) TestSourceValuesAreNotMutated() {
+	s.Run("Given a source holding lists of maps", func() {
+		type stubConfig struct {
+			D struct {
+				Hosts []struct {
+					Name string `snout:"name"`
+				} `snout:"hosts" merge:"append"`
+			} `snout:"d"`
+		}
+
+		values := func() stubSource {
+			return stubSource{"d": map[string]any{"hosts": []any{map[string]any{"Name": "source"}}}}
+		}
+
+		source := values()
+
+		s.Run("When Kernels load it along values appending to it", func() {
+			kernel := snout.Kernel[stubConfig]{RunE: func(context.Context, stubConfig) error { return nil }}
+
+			for i := 0; i < 2; i++ {
+				err := kernel.Bootstrap(context.TODO(), snout.WithSource(source),
+					snout.WithSource(stubSource{"d.hosts": []any{map[string]any{"name": "appended"}}}),
+					snout.WithArgs()).Initialize()
+				s.Require().NoError(err)
+			}
+
+			s.Run("Then the values held by the source are left untouched", func() {
+				s.Require().Equal(values(), source)
+			})
+		})
+	})
+}
+
+func (s *snoutSuite) TestSourceError() {
+	s.Run("Given a source failing to load", func() {
+		type stubConfig struct {
+			A string `snout:"a"`
+		}
+
+		s.Run("When the configuration is Checked", func() {
+			kernel := snout.Kernel[stubConfig]{}
+
+			err := kernel.Bootstrap(context.TODO(), snout.WithSource(failingSource{})).Check()
+
+			s.Run("Then a config error is returned", func() {
+				s.Require().ErrorIs(err, snout.ErrConfig)
+				s.Require().ErrorContains(err, "unreachable")
+			})
+		})
+	})
+}
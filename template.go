@@ -0,0 +1,217 @@
+package snout
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Format is a document format snout can render.
+type Format string
+
+const (
+	// FormatEnv renders KEY=value lines using the environment variable names snout binds.
+	FormatEnv Format = "env"
+	// FormatYAML renders a YAML document keyed by the snout tags.
+	FormatYAML Format = "yaml"
+	// FormatJSON renders a JSON document keyed by the snout tags.
+	FormatJSON Format = "json"
+	// FormatMarkdown renders a Markdown reference table.
+	FormatMarkdown Format = "markdown"
+	// FormatHTML renders an HTML reference table.
+	FormatHTML Format = "html"
+	// FormatSchema renders the key, env var, flag, type and default of every key as aligned plain text, meant to be
+	// diffed against a golden file.
+	FormatSchema Format = "schema"
+)
+
+// ErrUnknownFormat is an error indicating a format snout cannot render.
+var ErrUnknownFormat = errors.New("unknown format")
+
+// WriteTemplate writes a sample configuration document for T in the given format. Defaults are filled in, required
+// fields are flagged and secret fields are left blank.
+func (k *Kernel[T]) WriteTemplate(w io.Writer, format Format, opts ...Options) error {
+	kernelOpts := NewKernelOptions()
+	for _, opt := range opts {
+		opt(kernelOpts)
+	}
+
+	return writeTemplate(w, reflect.TypeOf((*T)(nil)).Elem(), format, kernelOpts)
+}
+
+// GenerateTemplates writes a commented .env.example along with sample YAML and JSON config files for T into dir.
+func (k *Kernel[T]) GenerateTemplates(dir string, opts ...Options) error {
+	kernelOpts := NewKernelOptions()
+	for _, opt := range opts {
+		opt(kernelOpts)
+	}
+
+	return generateTemplates(dir, reflect.TypeOf((*T)(nil)).Elem(), kernelOpts)
+}
+
+// generateTemplates writes the sample documents for every template format into dir.
+func generateTemplates(dir string, t reflect.Type, options *KernelOptions) error {
+	name := options.ServiceName
+	if name == "" {
+		name = "config"
+	}
+
+	files := map[Format]string{
+		FormatEnv:  ".env.example",
+		FormatYAML: name + ".example.yaml",
+		FormatJSON: name + ".example.json",
+	}
+
+	for _, format := range []Format{FormatEnv, FormatYAML, FormatJSON} {
+		var buf bytes.Buffer
+		if err := writeTemplate(&buf, t, format, options); err != nil {
+			return err
+		}
+
+		path := filepath.Join(dir, files[format])
+		if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+			return fmt.Errorf("writing template %s: %w", path, err)
+		}
+
+		logger.Info("Generated config template", slog.String("file", path))
+	}
+
+	return nil
+}
+
+// writeTemplate renders the sample document for t in the given format.
+func writeTemplate(w io.Writer, t reflect.Type, format Format, options *KernelOptions) error {
+	doc := document{
+		fields: configFields(t, namingOf(options)),
+		value:  templateValue,
+		comment: func(f configField) string {
+			return templateComment(f, options.Variants)
+		},
+	}
+
+	if format == FormatEnv {
+		doc.value = func(f configField) any {
+			if f.collection() {
+				return templateValue(f)
+			}
+
+			return templateString(f)
+		}
+	}
+
+	return doc.write(w, format, options.Env)
+}
+
+// templateComment describes a field for the comment written next to it in a template, listing the variants
+// registered for interface fields.
+func templateComment(f configField, variants map[reflect.Type]map[string]reflect.Type) string {
+	parts := []string{f.typ().String()}
+
+	if f.required() {
+		parts = append(parts, "required")
+	}
+
+	if f.secret() {
+		parts = append(parts, "secret")
+	}
+
+	if value, ok := f.defaultValue(); ok && !f.secret() {
+		parts = append(parts, "default: "+value)
+	}
+
+	if f.variant() {
+		parts = append(parts, f.discriminator()+": "+strings.Join(variantNames(variants[f.typ()]), " | "))
+	}
+
+	comment := fmt.Sprintf("%s (%s)", f.key(), strings.Join(parts, ", "))
+	if desc := f.description(); desc != "" {
+		comment += "\n" + desc
+	}
+
+	return comment
+}
+
+// templateString returns the sample value of a field as text.
+func templateString(f configField) string {
+	if f.secret() {
+		return ""
+	}
+
+	value, _ := f.defaultValue()
+
+	return value
+}
+
+// templateValue returns the sample value of a field typed after its kind, so that it encodes as a JSON bool, number,
+// string, array or object. Interface fields get a section holding their discriminator.
+func templateValue(f configField) any {
+	if f.variant() {
+		return map[string]any{f.discriminator(): ""}
+	}
+
+	value, ok := f.defaultValue()
+	if f.secret() || !ok {
+		return zeroValue(f.typ())
+	}
+
+	if f.typ() == reflect.TypeOf(time.Duration(0)) {
+		return value
+	}
+
+	switch f.typ().Kind() {
+	case reflect.Bool:
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return n
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if n, err := strconv.ParseUint(value, 10, 64); err == nil {
+			return n
+		}
+	case reflect.Float32, reflect.Float64:
+		if n, err := strconv.ParseFloat(value, 64); err == nil {
+			return n
+		}
+	case reflect.Slice:
+		// the default is decoded as a single item at load time, commas included
+		return []string{value}
+	}
+
+	return value
+}
+
+// zeroValue returns the sample value of a field without default.
+func zeroValue(t reflect.Type) any {
+	switch t {
+	case reflect.TypeOf(time.Duration(0)):
+		return "0s"
+	case reflect.TypeOf(Feature{}):
+		return false
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return false
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return 0
+	case reflect.Slice, reflect.Array:
+		return []any{}
+	case reflect.Map:
+		return map[string]any{}
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,199 @@
+package snout_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/chiguirez/snout/v3"
+)
+
+type templateConfig struct {
+	Kafka struct {
+		BrokerAddress string        `snout:"broker_address" validate:"required"`
+		Timeout       time.Duration `snout:"timeout" default:"5s"`
+		Retries       int           `snout:"retries" default:"3"`
+	} `snout:"kafka"`
+	Password string `snout:"password" secret:"true" default:"hunter2"`
+}
+
+func (s *snoutSuite) TestEnvTemplate() {
+	s.Run("Given a config Struct with defaults, required and secret fields", func() {
+		kernel := snout.Kernel[templateConfig]{}
+
+		s.Run("When an env template is written with Prefix", func() {
+			var buf bytes.Buffer
+
+			err := kernel.WriteTemplate(&buf, snout.FormatEnv, snout.WithEnvVarPrefix("APP"))
+			s.Require().NoError(err)
+
+			s.Run("Then every env var is listed and commented", func() {
+				s.Require().Equal(`# kafka.broker_address (string, required)
+APP_KAFKA_BROKER_ADDRESS=
+
+# kafka.timeout (time.Duration, default: 5s)
+APP_KAFKA_TIMEOUT=5s
+
+# kafka.retries (int, default: 3)
+APP_KAFKA_RETRIES=3
+
+# password (string, secret)
+APP_PASSWORD=
+`, buf.String())
+			})
+		})
+	})
+}
+
+func (s *snoutSuite) TestYAMLTemplate() {
+	s.Run("Given a config Struct with defaults, required and secret fields", func() {
+		kernel := snout.Kernel[templateConfig]{}
+
+		s.Run("When a YAML template is written", func() {
+			var buf bytes.Buffer
+
+			err := kernel.WriteTemplate(&buf, snout.FormatYAML)
+			s.Require().NoError(err)
+
+			s.Run("Then defaults are filled in and secrets blanked", func() {
+				s.Require().Equal(`kafka:
+  # kafka.broker_address (string, required)
+  broker_address: ""
+  # kafka.timeout (time.Duration, default: 5s)
+  timeout: 5s
+  # kafka.retries (int, default: 3)
+  retries: 3
+# password (string, secret)
+password: ""
+`, buf.String())
+			})
+		})
+	})
+}
+
+func (s *snoutSuite) TestJSONTemplate() {
+	s.Run("Given a config Struct with defaults, required and secret fields", func() {
+		kernel := snout.Kernel[templateConfig]{}
+
+		s.Run("When a JSON template is written", func() {
+			var buf bytes.Buffer
+
+			err := kernel.WriteTemplate(&buf, snout.FormatJSON)
+			s.Require().NoError(err)
+
+			s.Run("Then defaults are filled in with their types", func() {
+				s.Require().JSONEq(`{
+  "kafka": {"broker_address": "", "timeout": "5s", "retries": 3},
+  "password": ""
+}`, buf.String())
+			})
+		})
+	})
+}
+
+func (s *snoutSuite) TestTemplateSliceDefault() {
+	s.Run("Given a config Struct with a slice default holding a comma", func() {
+		type stubConfig struct {
+			Tags []string `snout:"tags" default:"a,b"`
+		}
+
+		cfgChan := make(chan stubConfig, 1)
+
+		kernel := snout.Kernel[stubConfig]{RunE: func(_ context.Context, config stubConfig) error {
+			cfgChan <- config
+
+			return nil
+		}}
+
+		s.Run("When its YAML and JSON templates are loaded", func() {
+			err := kernel.Bootstrap(context.TODO(), snout.WithArgs(), snout.WithEnvVarFolderLocation(s.T().TempDir())).
+				Initialize()
+			s.Require().NoError(err)
+
+			defaults := <-cfgChan
+
+			for _, format := range []snout.Format{snout.FormatYAML, snout.FormatJSON} {
+				var buf bytes.Buffer
+
+				s.Require().NoError(kernel.WriteTemplate(&buf, format))
+				s.Require().NoError(kernel.Bootstrap(context.TODO(), snout.WithArgs(),
+					snout.WithConfigReader(&buf, format)).Initialize())
+
+				s.Run("Then it loads as the defaults do with "+string(format), func() {
+					s.Require().Equal([]string{"a,b"}, defaults.Tags)
+					s.Require().Equal(defaults, <-cfgChan)
+				})
+			}
+		})
+	})
+}
+
+func (s *snoutSuite) TestGenerateFlag() {
+	s.Run("Given a kernel started with --snout-generate", func() {
+		dir := s.T().TempDir()
+		ran := false
+
+		kernel := snout.Kernel[templateConfig]{RunE: func(context.Context, templateConfig) error {
+			ran = true
+
+			return nil
+		}}
+
+		s.Run("When Kernel is Initialized", func() {
+			err := kernel.Bootstrap(
+				context.TODO(),
+				snout.WithServiceName("svc"),
+				snout.WithArgs("--snout-generate="+dir),
+			).Initialize()
+			s.Require().NoError(err)
+
+			s.Run("Then templates are written and the kernel is not run", func() {
+				s.Require().False(ran)
+
+				for _, name := range []string{".env.example", "svc.example.yaml", "svc.example.json"} {
+					_, err := os.Stat(filepath.Join(dir, name))
+					s.Require().NoError(err)
+				}
+			})
+		})
+	})
+}
+
+func (s *snoutSuite) TestCollectionTemplate() {
+	s.Run("Given a config Struct with lists, maps and variants", func() {
+		kernel := snout.Kernel[dumpCollectionConfig]{}
+		opts := []snout.Options{snout.WithEnvVarPrefix("APP"), snout.WithVariant[queue]("sqs", &dumpQueue{})}
+
+		s.Run("When an env template is written", func() {
+			var buf bytes.Buffer
+
+			err := kernel.WriteTemplate(&buf, snout.FormatEnv, opts...)
+			s.Require().NoError(err)
+
+			s.Run("Then collections are written as JSON and variants list their types", func() {
+				s.Require().Equal(`# upstreams ([]snout_test.dumpUpstream)
+APP_UPSTREAMS=[]
+
+# tenants (map[string]snout_test.dumpUpstream)
+APP_TENANTS={}
+
+# queue (snout_test.queue, type: sqs)
+APP_QUEUE={"type":""}
+`, buf.String())
+			})
+		})
+
+		s.Run("When a YAML template is written", func() {
+			var buf bytes.Buffer
+
+			err := kernel.WriteTemplate(&buf, snout.FormatYAML, opts...)
+			s.Require().NoError(err)
+
+			s.Run("Then variants get a section holding their discriminator", func() {
+				s.Require().Contains(buf.String(), "# queue (snout_test.queue, type: sqs)\nqueue:\n  type: \"\"\n")
+			})
+		})
+	})
+}
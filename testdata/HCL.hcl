@@ -0,0 +1,10 @@
+a = "a"
+b = 1
+c = true
+e = "30m"
+
+d {
+  a = "da"
+  b = 3.1415
+  c = false
+}
@@ -0,0 +1,119 @@
+package snout
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// ErrUnknownVariant is an error indicating the discriminator of a section decoded into an interface names no
+// variant registered with WithVariant.
+var ErrUnknownVariant = errors.New("unknown variant")
+
+// WithVariant registers variant, a struct or pointer to struct implementing the interface I, in KernelOptions as the
+// type sections decoded into fields of type I hold when their discriminator key is name. The discriminator key is
+// type unless named by a discriminator tag on the field:
+//
+//	snout.WithVariant[Queue]("sqs", &SQSQueue{})
+//	snout.WithVariant[Queue]("kafka", &KafkaQueue{})
+func WithVariant[I any](name string, variant I) Options {
+	iface := reflect.TypeOf((*I)(nil)).Elem()
+
+	return func(kernel *KernelOptions) {
+		if kernel.Variants == nil {
+			kernel.Variants = map[reflect.Type]map[string]reflect.Type{}
+		}
+
+		if kernel.Variants[iface] == nil {
+			kernel.Variants[iface] = map[string]reflect.Type{}
+		}
+
+		kernel.Variants[iface][name] = reflect.TypeOf(variant)
+	}
+}
+
+// variant reports whether the field is an interface with methods, holding one of the variants registered for it.
+func (f configField) variant() bool {
+	return f.typ().Kind() == reflect.Interface && f.typ().NumMethod() > 0
+}
+
+// discriminator returns the key of the section of the field naming its variant, from its discriminator tag, type by
+// default.
+func (f configField) discriminator() string {
+	if name := f.field.Tag.Get("discriminator"); name != "" {
+		return name
+	}
+
+	return "type"
+}
+
+// resolveVariants replaces the sections of settings decoded into the interface fields of t with the variants their
+// discriminators name, decoded and with their defaults set, so that mapstructure assigns them to the fields.
+func resolveVariants(settings map[string]any, t reflect.Type, n naming, variants map[reflect.Type]map[string]reflect.Type) error {
+	for _, f := range configFields(t, n) {
+		if !f.variant() {
+			continue
+		}
+
+		section, ok := nestedValue(settings, f.key()).(map[string]any)
+		if !ok {
+			continue
+		}
+
+		name, ok := section[f.discriminator()].(string)
+		if !ok || name == "" {
+			return fmt.Errorf("%w: %s: missing %s", ErrUnknownVariant, f.key(), f.discriminator())
+		}
+
+		variant, ok := variants[f.typ()][name]
+		if !ok {
+			return fmt.Errorf("%w: %s: %s %q, expecting one of %s", ErrUnknownVariant, f.key(), f.discriminator(),
+				name, strings.Join(variantNames(variants[f.typ()]), ", "))
+		}
+
+		value := reflect.New(indirectType(variant))
+
+		for _, vf := range configFields(indirectType(variant), n) {
+			if def, ok := vf.defaultValue(); ok && nestedValue(section, vf.key()) == nil {
+				setNested(section, vf.key(), def)
+			}
+		}
+
+		if err := decodeSettings(section, value.Interface(), n, variants); err != nil {
+			return fmt.Errorf("%s: %w", f.key(), err)
+		}
+
+		if variant.Kind() != reflect.Ptr {
+			value = value.Elem()
+		}
+
+		setNested(settings, f.key(), value.Interface())
+	}
+
+	return nil
+}
+
+// variantNames returns the sorted names of variants.
+func variantNames(variants map[string]reflect.Type) []string {
+	names := make([]string, 0, len(variants))
+	for name := range variants {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// variantName returns the name variant is registered under among variants, or "" when it is not registered.
+func variantName(variants map[string]reflect.Type, variant reflect.Type) string {
+	for _, name := range variantNames(variants) {
+		if variants[name] == variant {
+			return name
+		}
+	}
+
+	return ""
+}
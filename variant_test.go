@@ -0,0 +1,131 @@
+package snout_test
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/chiguirez/snout/v3"
+)
+
+type queue interface {
+	Kind() string
+}
+
+type sqsQueue struct {
+	URL      string        `snout:"url" validate:"required"`
+	WaitTime time.Duration `snout:"wait_time" default:"20s"`
+}
+
+func (*sqsQueue) Kind() string { return "sqs" }
+
+type kafkaQueue struct {
+	Brokers []string `snout:"brokers" validate:"min=1"`
+	Topic   string   `snout:"topic" default:"events"`
+}
+
+func (kafkaQueue) Kind() string { return "kafka" }
+
+type variantConfig struct {
+	Queue    queue `snout:"queue" validate:"required"`
+	Fallback queue `snout:"fallback" discriminator:"kind"`
+}
+
+func (s *snoutSuite) TestVariants() {
+	bootstrap := func(opts ...snout.Options) (variantConfig, error) {
+		cfgChan := make(chan variantConfig, 1)
+
+		kernel := snout.Kernel[variantConfig]{RunE: func(_ context.Context, config variantConfig) error {
+			cfgChan <- config
+
+			return nil
+		}}
+
+		opts = append([]snout.Options{
+			snout.WithEnvVarPrefix("APP"),
+			snout.WithVariant[queue]("sqs", &sqsQueue{}),
+			snout.WithVariant[queue]("kafka", kafkaQueue{}),
+		}, opts...)
+
+		if err := kernel.Bootstrap(context.TODO(), opts...).Initialize(); err != nil {
+			return variantConfig{}, err
+		}
+
+		return <-cfgChan, nil
+	}
+
+	yaml := func(doc string) snout.Options {
+		return snout.WithConfigReader(strings.NewReader(doc), snout.FormatYAML)
+	}
+
+	env := func(vars map[string]string) snout.Options {
+		return snout.WithLookupEnv(func(name string) (string, bool) {
+			value, ok := vars[name]
+
+			return value, ok
+		})
+	}
+
+	s.Run("Given a config Struct with interface fields and registered variants", func() {
+		s.Run("When sections name their variants by discriminator", func() {
+			cfg, err := bootstrap(
+				yaml("queue:\n  type: kafka\n  brokers: [kafka:9092]\nfallback:\n  kind: sqs\n  url: https://sqs/q\n"),
+				snout.WithArgs(),
+			)
+
+			s.Run("Then each field holds its variant with defaults applied", func() {
+				s.Require().NoError(err)
+				s.Require().Equal(kafkaQueue{Brokers: []string{"kafka:9092"}, Topic: "events"}, cfg.Queue)
+				s.Require().Equal(&sqsQueue{URL: "https://sqs/q", WaitTime: 20 * time.Second}, cfg.Fallback)
+			})
+		})
+
+		s.Run("When a section is set as JSON from an env var", func() {
+			cfg, err := bootstrap(env(map[string]string{"APP_QUEUE": `{"type":"sqs","url":"https://sqs/q","wait_time":"5s"}`}),
+				snout.WithArgs())
+
+			s.Run("Then the variant is decoded from the document", func() {
+				s.Require().NoError(err)
+				s.Require().Equal(&sqsQueue{URL: "https://sqs/q", WaitTime: 5 * time.Second}, cfg.Queue)
+			})
+		})
+
+		s.Run("When a section names an unregistered variant", func() {
+			_, err := bootstrap(yaml("queue:\n  type: rabbit\n"), snout.WithArgs())
+
+			s.Run("Then loading fails listing the registered ones", func() {
+				s.Require().ErrorIs(err, snout.ErrUnknownVariant)
+				s.Require().ErrorContains(err, `queue: type "rabbit", expecting one of kafka, sqs`)
+			})
+		})
+
+		s.Run("When a section has no discriminator", func() {
+			_, err := bootstrap(yaml("queue:\n  url: https://sqs/q\n"), snout.WithArgs())
+
+			s.Run("Then loading fails naming the missing key", func() {
+				s.Require().ErrorIs(err, snout.ErrUnknownVariant)
+				s.Require().ErrorContains(err, "queue: missing type")
+			})
+		})
+
+		s.Run("When the chosen variant breaks its validate tags", func() {
+			_, err := bootstrap(yaml("queue:\n  type: sqs\n"), snout.WithArgs())
+
+			s.Run("Then the failing field is reported by its key within the section", func() {
+				var errs snout.ValidationErrors
+				s.Require().ErrorAs(err, &errs)
+				s.Require().Equal(snout.ValidationErrors{{Key: "queue.url", Rule: "required"}}, errs)
+			})
+		})
+
+		s.Run("When a required section is missing", func() {
+			_, err := bootstrap(snout.WithArgs())
+
+			s.Run("Then the field is reported as required", func() {
+				var errs snout.ValidationErrors
+				s.Require().ErrorAs(err, &errs)
+				s.Require().Equal(snout.ValidationErrors{{Key: "queue", Rule: "required"}}, errs)
+			})
+		})
+	})
+}